@@ -0,0 +1,131 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+// outlineChordOptions lists, for every key position of an outline, the chords that could start there.
+// Banks aren't decomposed greedily since e.g. -RBG is R and BG rather than RB and G.
+func outlineChordOptions(key string) [][]stenoChord {
+	var options [][]stenoChord
+	addBank := func(bank string, chords []stenoChord) {
+		for offset := range bank {
+			var starting []stenoChord
+			for _, chord := range chords {
+				if strings.HasPrefix(bank[offset:], chord.keys) {
+					starting = append(starting, chord)
+				}
+			}
+			if len(starting) == 0 {
+				starting = []stenoChord{{keys: bank[offset : offset+1], sounds: 1, spellings: []string{strings.ToLower(bank[offset : offset+1])}}}
+			}
+			options = append(options, starting)
+		}
+	}
+
+	for _, stroke := range strings.Split(key, "/") {
+		stroke = strings.TrimPrefix(stroke, "#")
+		if strings.HasPrefix(stroke, "-") {
+			addBank(stroke[1:], rightBankChords)
+			continue
+		}
+		parts := separateStrokeParts(stroke)
+		if !parts.Valid {
+			continue
+		}
+		addBank(parts.Left, leftBankChords)
+		vowels := strings.ReplaceAll(parts.Vowels, "*", "")
+		if vowels != "" {
+			spellings, ok := vowelChordSpellings[vowels]
+			if !ok {
+				spellings = []string{strings.ToLower(vowels)}
+			}
+			addBank(vowels, []stenoChord{{keys: vowels, sounds: 1, spellings: spellings}})
+		}
+		addBank(parts.Right, rightBankChords)
+	}
+	return options
+}
+
+// alignmentLetters lowercases the letters of a translation, returning "" for commands and
+// translations without letters, which aren't checked
+func alignmentLetters(value string) string {
+	if strings.HasPrefix(value, "{") || strings.HasPrefix(value, "=") {
+		return ""
+	}
+	var letters strings.Builder
+	for _, r := range strings.ToLower(value) {
+		if r >= 'a' && r <= 'z' {
+			letters.WriteRune(r)
+		} else if unicode.IsLetter(r) {
+			// accented letters can't be matched against the chord spellings
+			return ""
+		}
+	}
+	return letters.String()
+}
+
+// outlineAlignmentScore aligns the chords of an outline with the letters of its translation, e.g. TPH with n
+// and -FRB with rv, allowing chords and letters to be skipped. The score is the average of the share of
+// letters spelled by a chord and the share of keys in a chord that spells something, so 1 means every
+// chord spells its part of the word. Translations that can't be checked score 1.
+func outlineAlignmentScore(key, value string) float64 {
+	word := alignmentLetters(value)
+	options := outlineChordOptions(key)
+	if word == "" || len(options) == 0 {
+		return 1
+	}
+
+	type alignment struct {
+		letters int
+		keys    int
+	}
+	better := func(a, b alignment) bool {
+		return a.letters+a.keys > b.letters+b.keys
+	}
+	// best[i][j] is the best alignment of the first i keys with the first j letters
+	best := make([][]alignment, len(options)+1)
+	for i := range best {
+		best[i] = make([]alignment, len(word)+1)
+	}
+	for i := 0; i <= len(options); i++ {
+		for j := 0; j <= len(word); j++ {
+			current := best[i][j]
+			// skip a silent letter
+			if j < len(word) && better(current, best[i][j+1]) {
+				best[i][j+1] = current
+			}
+			if i == len(options) {
+				continue
+			}
+			for _, chord := range options[i] {
+				next := i + len(chord.keys)
+				// skip a chord that doesn't spell anything here
+				if better(current, best[next][j]) {
+					best[next][j] = current
+				}
+				for _, spelling := range chord.spellings {
+					if !strings.HasPrefix(word[j:], spelling) {
+						continue
+					}
+					matched := alignment{letters: current.letters + len(spelling), keys: current.keys + len(chord.keys)}
+					end := j + len(spelling)
+					if better(matched, best[next][end]) {
+						best[next][end] = matched
+					}
+					// doubled consonants like the pp in "happy" are written with a single chord
+					if spelling != "" && end < len(word) && word[end] == word[end-1] {
+						doubled := alignment{letters: matched.letters + 1, keys: matched.keys}
+						if better(doubled, best[next][end+1]) {
+							best[next][end+1] = doubled
+						}
+					}
+				}
+			}
+		}
+	}
+
+	result := best[len(options)][len(word)]
+	return (float64(result.letters)/float64(len(word)) + float64(result.keys)/float64(len(options))) / 2
+}
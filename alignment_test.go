@@ -0,0 +1,29 @@
+package main
+
+import "testing"
+
+func TestOutlineAlignmentScore(t *testing.T) {
+	tests := []struct {
+		name  string
+		key   string
+		value string
+		want  float64
+	}{
+		{name: "every chord spells the word", key: "TKEUS/TREU/PWAOUT", value: "distribute", want: 0.95},
+		{name: "doubled consonant", key: "HAP/KWREU", value: "happy", want: 1},
+		{name: "right hand chord", key: "SEFRB", value: "serv", want: 1},
+		{name: "proper name prefix", key: "#KAT", value: "Cat", want: 1},
+		{name: "command is not checked", key: "STKPW", value: "{^}", want: 1},
+		{name: "right hand chord with an alternative decomposition", key: "PHAEURBG", value: "mark", want: 1},
+		{name: "nonsense outline", key: "PWOEUPB", value: "cat", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := outlineAlignmentScore(tt.key, tt.value)
+			if got < tt.want-0.001 || got > tt.want+0.001 {
+				t.Fatalf("outlineAlignmentScore(%q, %q) = %v, want %v", tt.key, tt.value, got, tt.want)
+			}
+		})
+	}
+}
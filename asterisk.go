@@ -0,0 +1,73 @@
+package main
+
+import (
+	"slices"
+	"strings"
+)
+
+// withAsterisk adds the asterisk to a bank of vowels, keeping steno order (AO*EU)
+func withAsterisk(vowels string) string {
+	position := strings.IndexFunc(vowels, func(r rune) bool { return r == 'E' || r == 'U' })
+	if position == -1 {
+		position = len(vowels)
+	}
+	return vowels[:position] + "*" + vowels[position:]
+}
+
+// asteriskVariants are the outlines made by adding the asterisk to one stroke of an outline, last
+// stroke first, the way /A* and A*/ tell words apart from /A and A/
+func asteriskVariants(key string) []string {
+	strokes := strings.Split(key, "/")
+	var variants []string
+	for i := len(strokes) - 1; i >= 0; i-- {
+		parts := strokeParts(strokes[i])
+		if !parts.Valid || strings.Contains(parts.Vowels, "*") {
+			continue
+		}
+		parts.Vowels = withAsterisk(parts.Vowels)
+		stroke, ok := validMergedStroke(parts)
+		if !ok {
+			continue
+		}
+		variant := slices.Clone(strokes)
+		variant[i] = stroke
+		variants = append(variants, strings.Join(variant, "/"))
+	}
+	return variants
+}
+
+// addAsteriskFallback is tried when a candidate's outline already belongs to owner: the first
+// asterisk variant of the outline that is free and boundary safe is added instead, with the conflict
+// recorded in its provenance. Outlines that already have an asterisk don't fall back.
+func addAsteriskFallback(key, value, owner string, origin derivation, ctx *augmentationContext) bool {
+	if !ctx.asteriskFallback || strings.Contains(key, "*") {
+		return false
+	}
+	for _, variant := range asteriskVariants(key) {
+		if (*ctx.originalDictionary)[variant] == value || (*ctx.additionalEntries)[variant] == value {
+			return false
+		}
+		if addEntryIfNotPresent(variant, value, origin, ctx) {
+			record := ctx.provenance[variant]
+			record.AsteriskFor = key
+			record.ConflictsWith = owner
+			ctx.provenance[variant] = record
+			return true
+		}
+	}
+	return false
+}
+
+// addedOutline is the outline addEntryIfNotPresent added for key, which is an asterisk variant when
+// key was taken
+func (ctx *augmentationContext) addedOutline(key, value string) string {
+	if (*ctx.additionalEntries)[key] == value {
+		return key
+	}
+	for _, variant := range asteriskVariants(key) {
+		if (*ctx.additionalEntries)[variant] == value && ctx.provenance[variant].AsteriskFor == key {
+			return variant
+		}
+	}
+	return key
+}
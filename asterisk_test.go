@@ -0,0 +1,53 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAsteriskVariants(t *testing.T) {
+	tests := []struct {
+		key  string
+		want []string
+	}{
+		{key: "KAT", want: []string{"KA*T"}},
+		{key: "PHAEU/KER", want: []string{"PHAEU/K*ER", "PHA*EU/KER"}},
+		{key: "TK/-S", want: []string{"TK/*S", "TK*/-S"}},
+		{key: "A*/PHAEUZ", want: []string{"A*/PHA*EUZ"}},
+	}
+	for _, tt := range tests {
+		if got := asteriskVariants(tt.key); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("asteriskVariants(%q) = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestAddAsteriskFallback(t *testing.T) {
+	ctx := newTestAugmentationContext(map[string]string{
+		"KAT/HROG":  "catalog",
+		"KAT/HRO*G": "cat log",
+	})
+	origin := derivation{rule: ruleAlternateSplit, source: "KA/TA/HROG"}
+	if addEntryIfNotPresent("KAT/HROG", "catalogue", origin, ctx) {
+		t.Fatalf("expected no fallback without --asterisk_fallback, got %v", *ctx.additionalEntries)
+	}
+
+	ctx.asteriskFallback = true
+	if !addEntryIfNotPresent("KAT/HROG", "catalogue", origin, ctx) {
+		t.Fatalf("expected an asterisk variant to be added")
+	}
+	// the last stroke's variant is taken, so the asterisk goes in the first stroke
+	if (*ctx.additionalEntries)["KA*T/HROG"] != "catalogue" {
+		t.Fatalf("expected KA*T/HROG for catalogue, got %v", *ctx.additionalEntries)
+	}
+	record := ctx.provenance["KA*T/HROG"]
+	if record.Rule != ruleAlternateSplit || record.AsteriskFor != "KAT/HROG" || record.ConflictsWith != "catalog" {
+		t.Errorf("expected the conflict in the provenance, got %+v", record)
+	}
+	if got := ctx.addedOutline("KAT/HROG", "catalogue"); got != "KA*T/HROG" {
+		t.Errorf("addedOutline = %q, want KA*T/HROG", got)
+	}
+	if addEntryIfNotPresent("KAT/HROG", "catalogue", origin, ctx) || len(*ctx.additionalEntries) != 1 {
+		t.Errorf("expected the variant to only be added once, got %v", *ctx.additionalEntries)
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+const (
+	caseVariantsFirstWord = "first"
+	caseVariantsTitle     = "title"
+	caseVariantsOff       = "off"
+)
+
+// caseVariantPolicy controls the #-prefixed proper name variants and the lowercase
+// variants of #-prefixed entries that are added once all other passes are done
+type caseVariantPolicy struct {
+	mode      string
+	generated bool
+}
+
+func parseCaseVariantMode(mode string) (string, error) {
+	switch mode {
+	case caseVariantsFirstWord, caseVariantsTitle, caseVariantsOff:
+		return mode, nil
+	}
+	return "", fmt.Errorf("unknown case variant policy %q, expected one of %s, %s or %s",
+		mode, caseVariantsFirstWord, caseVariantsTitle, caseVariantsOff)
+}
+
+// addCaseVariants adds case variants for the original dictionary and, if the policy allows it,
+// for the generated entries. Variants are never derived from other variants.
+func addCaseVariants(policy caseVariantPolicy, ctx *augmentationContext) int {
+	if policy.mode == caseVariantsOff {
+		return 0
+	}
+
+	// take both key lists before adding anything so new variants are not revisited
+	sourceKeys := slices.DeleteFunc(ctx.frequencies.sortedKeys(ctx.originalDictionary), func(key string) bool {
+		return !ctx.augments(key, (*ctx.originalDictionary)[key])
+	})
+	var generatedKeys []string
+	generatedValues := make(map[string]string)
+	if policy.generated {
+		generatedKeys = ctx.frequencies.sortedKeys(ctx.additionalEntries)
+		for _, key := range generatedKeys {
+			generatedValues[key] = (*ctx.additionalEntries)[key]
+		}
+	}
+
+	addedCount := 0
+	addVariants := func(keys []string, values map[string]string) {
+		for _, key := range keys {
+			variantKey, variantValue, ok := caseVariant(policy, key, values[key])
+			if !ok {
+				continue
+			}
+			if addEntryIfNotPresent(variantKey, variantValue, derivation{rule: ruleCaseVariant, source: key}, ctx) {
+				addedCount++
+			}
+		}
+	}
+	addVariants(sourceKeys, *ctx.originalDictionary)
+	addVariants(generatedKeys, generatedValues)
+	return addedCount
+}
+
+// caseVariant returns the proper name variant of an entry without a leading #, or the
+// lowercase variant of an entry with one
+func caseVariant(policy caseVariantPolicy, key, value string) (string, string, bool) {
+	if !hasCaseableTranslation(value) {
+		return "", "", false
+	}
+	if strings.HasPrefix(key, "#") {
+		lowerCasedValue := lowerCaseWords(value)
+		if lowerCasedValue == value {
+			return "", "", false
+		}
+		return strings.TrimPrefix(key, "#"), lowerCasedValue, true
+	}
+
+	upperCasedValue := capitalizeWords(value, policy.mode == caseVariantsTitle)
+	if upperCasedValue == value {
+		return "", "", false
+	}
+	return "#" + key, upperCasedValue, true
+}
+
+// hasCaseableTranslation skips commands, punctuation and [foo|bar] style entries
+func hasCaseableTranslation(value string) bool {
+	if strings.HasPrefix(value, "{") || strings.HasPrefix(value, "=") {
+		return false
+	}
+	if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") && strings.Contains(value, "|") {
+		return false
+	}
+	return strings.IndexFunc(value, unicode.IsLetter) != -1
+}
+
+// capitalizeWords uppercases the first letter of the first word, or of every word if eachWord is set.
+// Leading punctuation such as quotes is skipped and all-caps acronyms are left alone.
+func capitalizeWords(s string, eachWord bool) string {
+	words := strings.Split(s, " ")
+	for i, word := range words {
+		runes := []rune(word)
+		letterIndex := firstLetterIndex(runes)
+		if letterIndex == -1 {
+			// words without letters like "--" don't count as the first word
+			continue
+		}
+		if !isAcronym(word) {
+			runes[letterIndex] = unicode.ToUpper(runes[letterIndex])
+			words[i] = string(runes)
+		}
+		if !eachWord {
+			break
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// lowerCaseWords lowercases capitalized words, leaving acronyms and mixed case words like McDonald alone
+func lowerCaseWords(s string) string {
+	words := strings.Split(s, " ")
+	for i, word := range words {
+		runes := []rune(word)
+		letterIndex := firstLetterIndex(runes)
+		if letterIndex == -1 || isAcronym(word) {
+			continue
+		}
+		restHasUpper := false
+		for _, r := range runes[letterIndex+1:] {
+			if unicode.IsUpper(r) {
+				restHasUpper = true
+				break
+			}
+		}
+		if restHasUpper {
+			continue
+		}
+		runes[letterIndex] = unicode.ToLower(runes[letterIndex])
+		words[i] = string(runes)
+	}
+	return strings.Join(words, " ")
+}
+
+func firstLetterIndex(runes []rune) int {
+	for i, r := range runes {
+		if unicode.IsLetter(r) {
+			return i
+		}
+	}
+	return -1
+}
+
+// isAcronym is true for words with at least two letters that are all uppercase, e.g. NASA or "U.S."
+func isAcronym(word string) bool {
+	letterCount := 0
+	for _, r := range word {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		if !unicode.IsUpper(r) {
+			return false
+		}
+		letterCount++
+	}
+	return letterCount >= 2
+}
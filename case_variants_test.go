@@ -0,0 +1,79 @@
+package main
+
+import "testing"
+
+func TestCapitalizeWords(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		eachWord bool
+		want     string
+	}{
+		{name: "single word", value: "sport", eachWord: false, want: "Sport"},
+		{name: "first word only", value: "new york", eachWord: false, want: "New york"},
+		{name: "title case", value: "new york", eachWord: true, want: "New York"},
+		{name: "leading quote", value: "\"quoted", eachWord: false, want: "\"Quoted"},
+		{name: "leading punctuation word", value: "-- dash", eachWord: false, want: "-- Dash"},
+		{name: "acronym left alone", value: "NASA", eachWord: false, want: "NASA"},
+		{name: "acronym in title case", value: "the USA way", eachWord: true, want: "The USA Way"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := capitalizeWords(tt.value, tt.eachWord)
+			if got != tt.want {
+				t.Fatalf("capitalizeWords(%q, %v) = %q, want %q", tt.value, tt.eachWord, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLowerCaseWords(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{name: "capitalized word", value: "Mark", want: "mark"},
+		{name: "multiple words", value: "New York", want: "new york"},
+		{name: "acronym left alone", value: "NASA Rocket", want: "NASA rocket"},
+		{name: "mixed case left alone", value: "McDonald", want: "McDonald"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := lowerCaseWords(tt.value)
+			if got != tt.want {
+				t.Fatalf("lowerCaseWords(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCaseVariant(t *testing.T) {
+	policy := caseVariantPolicy{mode: caseVariantsFirstWord, generated: true}
+	tests := []struct {
+		name      string
+		key       string
+		value     string
+		wantKey   string
+		wantValue string
+		wantOk    bool
+	}{
+		{name: "proper name variant", key: "SPORT", value: "sport", wantKey: "#SPORT", wantValue: "Sport", wantOk: true},
+		{name: "lowercase variant", key: "#PHAEURBG", value: "Mark", wantKey: "PHAEURBG", wantValue: "mark", wantOk: true},
+		{name: "already capitalized", key: "HEL/TPH/KWRA", value: "Helena", wantOk: false},
+		{name: "command", key: "STKPW", value: "{^}", wantOk: false},
+		{name: "punctuation", key: "SPHEUT", value: "...", wantOk: false},
+		{name: "alternatives", key: "TPAO", value: "[foo|bar]", wantOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotKey, gotValue, gotOk := caseVariant(policy, tt.key, tt.value)
+			if gotKey != tt.wantKey || gotValue != tt.wantValue || gotOk != tt.wantOk {
+				t.Fatalf("caseVariant(%q, %q) = (%q, %q, %v), want (%q, %q, %v)", tt.key, tt.value, gotKey, gotValue, gotOk, tt.wantKey, tt.wantValue, tt.wantOk)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// consonantChord is a consonant with the chords each hand writes it with, the CMU phonemes it
+// writes and the spellings they stand for on that hand, the usual spelling first. Chords without spellings on a hand, like *T for
+// TH, can be moved between strokes but aren't used to decompose a bank.
+type consonantChord struct {
+	left           string
+	right          string
+	phonemes       []string
+	sounds         int
+	leftSpellings  []string
+	rightSpellings []string
+}
+
+// consonantChords is the chord table everything else is built from. When a chord is listed for more
+// than one consonant, the first one is used to move it to the other hand.
+var consonantChords = []consonantChord{
+	{left: "STKPW", right: "Z", phonemes: []string{"Z"}, sounds: 1, leftSpellings: []string{"z"}, rightSpellings: []string{"z", "s", "se"}},                           // Z
+	{left: "SKWR", right: "PBLG", phonemes: []string{"JH"}, sounds: 1, leftSpellings: []string{"j", "g", "dg"}, rightSpellings: []string{"j", "dge", "ge", "g"}},      // J
+	{left: "TKPW", right: "G", phonemes: []string{"G"}, sounds: 1, leftSpellings: []string{"g", "gh"}, rightSpellings: []string{"g", "ing"}},                          // G
+	{left: "KWR", phonemes: []string{"Y"}, sounds: 1, leftSpellings: []string{"y", ""}},                                                                               // Y
+	{left: "TPH", right: "PB", phonemes: []string{"N"}, sounds: 1, leftSpellings: []string{"n", "kn", "gn"}, rightSpellings: []string{"n", "kn", "gn"}},               // N
+	{left: "KW", sounds: 2, leftSpellings: []string{"qu"}},                                                                                                            // QU
+	{left: "KP", right: "BGS", sounds: 2, leftSpellings: []string{"x", "ex"}, rightSpellings: []string{"x", "ks", "cks", "ction", "cs"}},                              // X
+	{left: "KH", right: "FP", phonemes: []string{"CH"}, sounds: 1, leftSpellings: []string{"ch", "tch"}, rightSpellings: []string{"ch", "tch"}},                       // CH
+	{left: "SH", right: "RB", phonemes: []string{"SH", "ZH"}, sounds: 1, leftSpellings: []string{"sh", "ti", "ci", "s"}, rightSpellings: []string{"sh"}},              // SH
+	{left: "TP", right: "F", phonemes: []string{"F"}, sounds: 1, leftSpellings: []string{"f", "ph"}, rightSpellings: []string{"f", "v", "s", "ph"}},                   // F
+	{left: "SR", right: "F", phonemes: []string{"V"}, sounds: 1, leftSpellings: []string{"v"}},                                                                        // V
+	{left: "TH", right: "*T", phonemes: []string{"TH", "DH"}, sounds: 1, leftSpellings: []string{"th"}},                                                               // TH
+	{left: "PW", right: "B", phonemes: []string{"B"}, sounds: 1, leftSpellings: []string{"b"}, rightSpellings: []string{"b"}},                                         // B
+	{left: "TK", right: "D", phonemes: []string{"D"}, sounds: 1, leftSpellings: []string{"d"}, rightSpellings: []string{"d", "ed"}},                                   // D
+	{left: "PH", right: "PL", phonemes: []string{"M"}, sounds: 1, leftSpellings: []string{"m"}, rightSpellings: []string{"m", "mb"}},                                  // M
+	{left: "HR", right: "L", phonemes: []string{"L"}, sounds: 1, leftSpellings: []string{"l"}, rightSpellings: []string{"l"}},                                         // L
+	{left: "K", right: "BG", phonemes: []string{"K"}, sounds: 1, leftSpellings: []string{"k", "c", "ck", "ch"}, rightSpellings: []string{"k", "ck", "c", "ke", "ch"}}, // K
+	{left: "S", right: "S", phonemes: []string{"S"}, sounds: 1, leftSpellings: []string{"s", "c", "ss"}, rightSpellings: []string{"s", "c", "se", "ce"}},
+	{left: "T", right: "T", phonemes: []string{"T"}, sounds: 1, leftSpellings: []string{"t"}, rightSpellings: []string{"t", "th"}},
+	{left: "P", right: "P", phonemes: []string{"P"}, sounds: 1, leftSpellings: []string{"p"}, rightSpellings: []string{"p"}},
+	{left: "R", right: "R", phonemes: []string{"R"}, sounds: 1, leftSpellings: []string{"r", "wr", "rh"}, rightSpellings: []string{"r"}},
+	{left: "W", phonemes: []string{"W"}, sounds: 1, leftSpellings: []string{"w", "wh"}},
+	{left: "H", phonemes: []string{"HH"}, sounds: 1, leftSpellings: []string{"h"}},
+	{left: "Z", sounds: 1, leftSpellings: []string{"z"}},
+	{left: "V", sounds: 1, leftSpellings: []string{"v"}},
+	{right: "FRPB", sounds: 2, rightSpellings: []string{"rch", "nch"}},
+	{right: "FRP", sounds: 2, rightSpellings: []string{"mp"}},
+	{right: "FRB", sounds: 2, rightSpellings: []string{"rv", "rf"}},
+	{right: "PBG", phonemes: []string{"NG"}, sounds: 1, rightSpellings: []string{"ng", "nk", "nc"}},
+	{right: "GS", sounds: 2, rightSpellings: []string{"tion", "sion", "cian", "ss"}},
+	{right: "LG", sounds: 2, rightSpellings: []string{"lch", "lge", "lk"}},
+	{right: "FT", sounds: 2, rightSpellings: []string{"st", "ft"}},
+}
+
+// alteredRhsLetters maps left hand chords to the right hand chords for the same consonant
+var alteredRhsLetters = chordMoves(func(chord consonantChord) (string, string) { return chord.left, chord.right })
+
+// alteredLhsLetters maps right hand chords to the left hand chords for the same consonant
+var alteredLhsLetters = chordMoves(func(chord consonantChord) (string, string) { return chord.right, chord.left })
+
+// chordMoves maps the chords of one hand to the other hand's chord for the same consonant
+func chordMoves(hands func(chord consonantChord) (from, to string)) map[string]string {
+	moves := make(map[string]string)
+	for _, chord := range consonantChords {
+		from, to := hands(chord)
+		if _, ok := moves[from]; !ok && from != "" && to != "" {
+			moves[from] = to
+		}
+	}
+	return moves
+}
+
+// phonemeChords finds the chords for a CMU consonant phoneme
+func phonemeChords(phoneme string) (consonantChord, bool) {
+	for _, chord := range consonantChords {
+		if slices.Contains(chord.phonemes, phoneme) {
+			return chord, true
+		}
+	}
+	return consonantChord{}, false
+}
+
+// stenoChord is a group of keys written together for one or two sounds. The first spelling is
+// the usual one, the rest are other spellings the chord commonly stands for.
+type stenoChord struct {
+	keys      string
+	sounds    int
+	spellings []string
+}
+
+// left hand chords, longest first so that decomposing a bank picks e.g. TPH before TP
+var leftBankChords = bankChords(func(chord consonantChord) (string, []string) { return chord.left, chord.leftSpellings })
+
+// right hand chords, longest first
+var rightBankChords = bankChords(func(chord consonantChord) (string, []string) { return chord.right, chord.rightSpellings })
+
+// bankChords lists the chords of one hand that have spellings, longest first
+func bankChords(hand func(chord consonantChord) (string, []string)) []stenoChord {
+	var chords []stenoChord
+	for _, chord := range consonantChords {
+		if keys, spellings := hand(chord); len(spellings) > 0 {
+			chords = append(chords, stenoChord{keys: keys, sounds: chord.sounds, spellings: spellings})
+		}
+	}
+	slices.SortStableFunc(chords, func(a, b stenoChord) int {
+		return cmp.Compare(len(b.keys), len(a.keys))
+	})
+	return chords
+}
+
+// spellings of each vowel bank, ignoring the asterisk
+var vowelChordSpellings = map[string][]string{
+	"A":    {"a"},
+	"O":    {"o"},
+	"E":    {"e", "ea"},
+	"U":    {"u", "o"},
+	"AO":   {"oo", "ou", "u"},
+	"AE":   {"ea", "ae", "ee", "e", "ey", "ay", "y", "a"},
+	"AU":   {"au", "aw", "a", "o"},
+	"EU":   {"i", "y", "e", "u"},
+	"OE":   {"o", "oa", "ow", "oe"},
+	"OU":   {"ou", "ow"},
+	"AOE":  {"ee", "ea", "ie", "ei", "e", "y", "i", "ey"},
+	"AOU":  {"u", "oo", "ew", "ue", "ou", "eu"},
+	"AEU":  {"a", "ai", "ay", "ei", "ey", "eigh"},
+	"OEU":  {"oi", "oy"},
+	"AOEU": {"i", "y", "igh", "ie", "ye", "ai"},
+}
+
+// decomposeBank greedily splits a bank of keys in steno order into chords
+func decomposeBank(bank string, chords []stenoChord) []stenoChord {
+	var result []stenoChord
+	for len(bank) > 0 {
+		matched := false
+		for _, chord := range chords {
+			if strings.HasPrefix(bank, chord.keys) {
+				result = append(result, chord)
+				bank = bank[len(chord.keys):]
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			// unknown key, count it as a sound of its own
+			result = append(result, stenoChord{keys: bank[:1], sounds: 1, spellings: []string{strings.ToLower(bank[:1])}})
+			bank = bank[1:]
+		}
+	}
+	return result
+}
+
+// leftBankSoundCount is the number of consonant sounds written by the left hand of a stroke
+func leftBankSoundCount(stroke string) int {
+	parts := separateStrokeParts(stroke)
+	count := 0
+	for _, chord := range decomposeBank(strings.TrimPrefix(parts.Left, "#"), leftBankChords) {
+		count += chord.sounds
+	}
+	return count
+}
+
+// leftBankSpelling is the usual spelling of the consonants written by the left hand of a stroke
+func leftBankSpelling(stroke string) string {
+	parts := separateStrokeParts(stroke)
+	spelling := ""
+	for _, chord := range decomposeBank(strings.TrimPrefix(parts.Left, "#"), leftBankChords) {
+		spelling += chord.spellings[0]
+	}
+	return spelling
+}
@@ -0,0 +1,53 @@
+package main
+
+import "testing"
+
+func TestMoveChordsBetweenStrokes(t *testing.T) {
+	tests := []struct {
+		name     string
+		move     func(stroke, chord string) string
+		stroke   string
+		chord    string
+		expected string
+	}{
+		{name: "left hand N to the right", move: moveRhsPrefixToLhsStroke, stroke: "KA", chord: "TPH", expected: "KAPB"},
+		{name: "left hand TH to the right", move: moveRhsPrefixToLhsStroke, stroke: "PA", chord: "TH", expected: "PA*T"},
+		{name: "left hand X to the right", move: moveRhsPrefixToLhsStroke, stroke: "SEU", chord: "KP", expected: "SEUBGS"},
+		{name: "same keys on both hands", move: moveRhsPrefixToLhsStroke, stroke: "KA", chord: "T", expected: "KAT"},
+		{name: "right hand N to the left", move: moveLhsSuffixToRhsStroke, stroke: "-A", chord: "PB", expected: "TPHA"},
+		{name: "right hand F is moved as F, not V", move: moveLhsSuffixToRhsStroke, stroke: "AEU", chord: "F", expected: "TPAEU"},
+		{name: "right hand chord without a left hand one", move: moveLhsSuffixToRhsStroke, stroke: "EU", chord: "PBG", expected: "PBGEU"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if moved := test.move(test.stroke, test.chord); moved != test.expected {
+				t.Errorf("expected %s, got %s", test.expected, moved)
+			}
+		})
+	}
+}
+
+func TestLeftBankChords(t *testing.T) {
+	tests := []struct {
+		stroke   string
+		sounds   int
+		spelling string
+	}{
+		{stroke: "TPHAOE", sounds: 1, spelling: "n"},
+		{stroke: "STKPWAOE", sounds: 1, spelling: "z"},
+		{stroke: "KWAOE", sounds: 2, spelling: "qu"},
+		{stroke: "STRAOE", sounds: 3, spelling: "str"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.stroke, func(t *testing.T) {
+			if sounds := leftBankSoundCount(test.stroke); sounds != test.sounds {
+				t.Errorf("expected %d sounds, got %d", test.sounds, sounds)
+			}
+			if spelling := leftBankSpelling(test.stroke); spelling != test.spelling {
+				t.Errorf("expected %q, got %q", test.spelling, spelling)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"slices"
+	"strings"
+)
+
+const (
+	minCompoundComponentLength = 2
+	// only the shortest word outlines of each component are joined, so long words don't explode
+	maxCompoundComponentOutlines = 2
+)
+
+// compoundLexicon has the outlines of the words and affixes compounds are split into
+type compoundLexicon struct {
+	words map[string][]string
+	// affixes that can only start a compound, like over from {over^}
+	prefixes map[string][]string
+	// affixes that can only end a compound, like ness from {^ness}
+	suffixes map[string][]string
+	// the outlines of prefixes and suffixes
+	affixOutlines map[string]bool
+}
+
+func newCompoundLexicon(dict *map[string]string) *compoundLexicon {
+	lexicon := &compoundLexicon{
+		words:         make(map[string][]string),
+		prefixes:      make(map[string][]string),
+		suffixes:      make(map[string][]string),
+		affixOutlines: make(map[string]bool),
+	}
+	// shortest outlines first
+	for _, key := range sortedMapKeys(dict) {
+		value := (*dict)[key]
+		switch {
+		case inflectableWordRegex.MatchString(value):
+			lexicon.words[value] = append(lexicon.words[value], key)
+		case isPrefixTranslation(value) && inflectableWordRegex.MatchString(strings.Trim(value, "{^}")):
+			lexicon.prefixes[strings.Trim(value, "{^}")] = append(lexicon.prefixes[strings.Trim(value, "{^}")], key)
+			lexicon.affixOutlines[key] = true
+		case isSuffixTranslation(value) && inflectableWordRegex.MatchString(suffixText(value)):
+			lexicon.suffixes[suffixText(value)] = append(lexicon.suffixes[suffixText(value)], key)
+			lexicon.affixOutlines[key] = true
+		}
+	}
+	return lexicon
+}
+
+// componentOutlines returns the outlines a part of a word can be written with at a position of a
+// compound. Only the word outlines are capped, so a part that is also a prefix or suffix keeps its
+// affix outlines.
+func (lexicon *compoundLexicon) componentOutlines(part string, first, last bool) []string {
+	outlines := lexicon.words[part]
+	outlines = outlines[:min(len(outlines), maxCompoundComponentOutlines)]
+	if first {
+		outlines = append(outlines[:len(outlines):len(outlines)], lexicon.prefixes[part]...)
+	}
+	if last {
+		outlines = append(outlines[:len(outlines):len(outlines)], lexicon.suffixes[part]...)
+	}
+	return outlines
+}
+
+// splitCompound splits a word into parts of the lexicon, trying the longest first part first, and
+// returns the outlines each part can be written with
+func (lexicon *compoundLexicon) splitCompound(word string) [][]string {
+	var split func(start int) [][]string
+	split = func(start int) [][]string {
+		for end := len(word); end-start >= minCompoundComponentLength; end-- {
+			if start == 0 && end == len(word) {
+				continue
+			}
+			outlines := lexicon.componentOutlines(word[start:end], start == 0, end == len(word))
+			if len(outlines) == 0 {
+				continue
+			}
+			if end == len(word) {
+				return [][]string{outlines}
+			}
+			if rest := split(end); rest != nil {
+				return append([][]string{outlines}, rest...)
+			}
+		}
+		return nil
+	}
+	return split(0)
+}
+
+// compoundJoin is an outline made by joining the outlines of the parts of a compound
+type compoundJoin struct {
+	outline string
+	// the stroke positions where a word outline follows another word outline, like the H of TKOG/HOUS
+	wordSeams []int
+}
+
+// compoundSeams are the wordSeams of a compound added for value
+type compoundSeams struct {
+	value     string
+	positions []int
+}
+
+// allowedSplits returns the word seams of key if it was added as a compound of value. These splits
+// read as the words of the compound, so they aren't word boundary conflicts.
+func (ctx *augmentationContext) allowedSplits(key, value string) []int {
+	if seams, ok := ctx.compoundSeams[key]; ok && seams.value == value {
+		return seams.positions
+	}
+	return nil
+}
+
+// compoundOutlines joins every combination of the outlines of the parts, keeping track of where word
+// outlines meet
+func compoundOutlines(parts [][]string, affixOutlines map[string]bool) []compoundJoin {
+	if len(parts) == 0 {
+		return nil
+	}
+	type join struct {
+		compoundJoin
+		strokes    int
+		endsInWord bool
+	}
+	joins := []join{{}}
+	for _, part := range parts {
+		var joined []join
+		for _, previous := range joins {
+			for _, partOutline := range part {
+				next := previous
+				next.outline = strings.TrimPrefix(previous.outline+"/"+partOutline, "/")
+				isWord := !affixOutlines[partOutline]
+				if previous.endsInWord && isWord {
+					next.wordSeams = append(slices.Clip(previous.wordSeams), previous.strokes)
+				}
+				next.strokes += strings.Count(partOutline, "/") + 1
+				next.endsInWord = isWord
+				joined = append(joined, next)
+			}
+		}
+		joins = joined
+	}
+	outlines := make([]compoundJoin, 0, len(joins))
+	for _, joined := range joins {
+		outlines = append(outlines, joined.compoundJoin)
+	}
+	return outlines
+}
+
+// addCompounds adds outlines for words of the source dictionaries made by joining the outlines of
+// the affixes and words they are made of, like overcome from {over^} and come. Where two word
+// outlines meet, like TKOG/HOUS for doghouse, the join reads as the separate words, so it is only
+// added if the first split oracle that knows the word puts a syllable boundary at every stroke
+// boundary of the outline. Those seams then aren't word boundary conflicts for the compound.
+func addCompounds(splitOracles []splitOracle, ctx *augmentationContext) int {
+	lexicon := newCompoundLexicon(ctx.originalDictionary)
+	if ctx.compoundSeams == nil {
+		ctx.compoundSeams = make(map[string]compoundSeams)
+	}
+	added := 0
+	for _, key := range ctx.frequencies.sortedKeys(ctx.originalDictionary) {
+		value := (*ctx.originalDictionary)[key]
+		if !inflectableWordRegex.MatchString(value) || !ctx.augments(key, value) || lexicon.words[value][0] != key {
+			continue
+		}
+		for _, join := range compoundOutlines(lexicon.splitCompound(value), lexicon.affixOutlines) {
+			if len(join.wordSeams) > 0 {
+				if !hasSyllableBoundaries(value, strings.Split(join.outline, "/"), splitOracles) {
+					continue
+				}
+				ctx.compoundSeams[join.outline] = compoundSeams{value: value, positions: join.wordSeams}
+			}
+			if addEntryIfNotPresent(join.outline, value, derivation{rule: ruleCompound, source: key}, ctx) {
+				added++
+			}
+		}
+	}
+	return added
+}
+
+// hasSyllableBoundaries reports whether the first oracle that knows value lines up the strokes with
+// its syllables without rejecting any of their boundaries
+func hasSyllableBoundaries(value string, strokes []string, oracles []splitOracle) bool {
+	for _, oracle := range oracles {
+		if oracle.knowsWord(value) {
+			judgement := oracle.judgeSplit(value, strokes)
+			return judgement.known && !judgement.rejected
+		}
+	}
+	return false
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"io"
+	"log"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitCompound(t *testing.T) {
+	lexicon := newCompoundLexicon(&map[string]string{
+		"TKOG":      "dog",
+		"TKO*G":     "dog",
+		"TKOG/TKOG": "dog",
+		"HOUS":      "house",
+		"HO":        "ho",
+		"-S":        "use",
+		"TPAOEUR":   "fire",
+		"TPAOEUT":   "fight",
+		"-ER":       "{^er}",
+		"OEFR":      "{over^}",
+		"KOPL":      "come",
+		"A":         "a",
+		"ER":        "er",
+		"*ER":       "er",
+		"ERB":       "er",
+	})
+
+	tests := []struct {
+		word     string
+		expected [][]string
+	}{
+		{"doghouse", [][]string{{"TKOG", "TKO*G"}, {"HOUS"}}},
+		// er is also a word with more outlines than are joined, but the suffix outline is kept
+		{"firefighter", [][]string{{"TPAOEUR"}, {"TPAOEUT"}, {"ER", "*ER", "-ER"}}},
+		{"overcome", [][]string{{"OEFR"}, {"KOPL"}}},
+		{"dog", nil},
+		{"adog", nil},
+		{"dogcat", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.word, func(t *testing.T) {
+			parts := lexicon.splitCompound(test.word)
+			if !reflect.DeepEqual(parts, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, parts)
+			}
+		})
+	}
+}
+
+func TestCompoundOutlines(t *testing.T) {
+	affixOutlines := map[string]bool{"OEFR": true, "-ER": true}
+	tests := []struct {
+		name     string
+		parts    [][]string
+		expected []compoundJoin
+	}{
+		{
+			name:     "prefix and word",
+			parts:    [][]string{{"OEFR"}, {"HAOET", "HAO*ET"}},
+			expected: []compoundJoin{{outline: "OEFR/HAOET"}, {outline: "OEFR/HAO*ET"}},
+		},
+		{
+			name:     "words",
+			parts:    [][]string{{"TKOG"}, {"HOUS"}},
+			expected: []compoundJoin{{outline: "TKOG/HOUS", wordSeams: []int{1}}},
+		},
+		{
+			name:     "words and a suffix",
+			parts:    [][]string{{"TPAOEUR"}, {"TPAOEUT", "TPAOEU/TEU"}, {"-ER"}},
+			expected: []compoundJoin{{outline: "TPAOEUR/TPAOEUT/-ER", wordSeams: []int{1}}, {outline: "TPAOEUR/TPAOEU/TEU/-ER", wordSeams: []int{1}}},
+		},
+		{
+			name:     "seam after a word with more than one stroke",
+			parts:    [][]string{{"TPAOEU/TEU"}, {"TKOG"}},
+			expected: []compoundJoin{{outline: "TPAOEU/TEU/TKOG", wordSeams: []int{2}}},
+		},
+		{
+			name:     "no parts",
+			parts:    nil,
+			expected: nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			joins := compoundOutlines(test.parts, affixOutlines)
+			if !reflect.DeepEqual(joins, test.expected) {
+				t.Errorf("expected %+v, got %+v", test.expected, joins)
+			}
+		})
+	}
+}
+
+func TestAddCompounds(t *testing.T) {
+	ctx := newTestAugmentationContext(map[string]string{
+		"TKOG":         "dog",
+		"HOUS":         "house",
+		"TKOG/HOUZ":    "doghouse",
+		"OEFR":         "{over^}",
+		"KOPL":         "come",
+		"OE/SRER/KOPL": "overcome",
+	})
+
+	added := addCompounds(nil, ctx)
+
+	// without a syllable boundary from an oracle TKOG/HOUS is left to read as dog house, OEFR/KOPL
+	// can only be overcome
+	expected := map[string]string{"OEFR/KOPL": "overcome"}
+	if added != 1 || !reflect.DeepEqual(*ctx.additionalEntries, expected) {
+		t.Errorf("expected %v, got %d entries: %v", expected, added, *ctx.additionalEntries)
+	}
+	if record := ctx.provenance["OEFR/KOPL"]; record.Rule != ruleCompound || record.Source != "OE/SRER/KOPL" {
+		t.Errorf("expected OEFR/KOPL to be a compound of OE/SRER/KOPL, got %+v", record)
+	}
+}
+
+func TestAddWordCompounds(t *testing.T) {
+	pronunciations := &PronunciationDictionary{pronunciations: map[string][][]string{
+		"doghouse":    {strings.Fields("D AO1 G HH AW2 S")},
+		"firefighter": {strings.Fields("F AY1 R F AY2 T ER0")},
+		"notable":     {strings.Fields("N OW1 T AH0 B AH0 L")},
+	}}
+	tests := []struct {
+		name     string
+		dict     map[string]string
+		oracles  []splitOracle
+		expected map[string]string
+	}{
+		{
+			name: "doghouse",
+			dict: map[string]string{
+				"TKOG":      "dog",
+				"HOUS":      "house",
+				"TKOG/HOUZ": "doghouse",
+			},
+			oracles:  []splitOracle{pronunciations},
+			expected: map[string]string{"TKOG/HOUS": "doghouse"},
+		},
+		{
+			name: "firefighter",
+			dict: map[string]string{
+				"TPAOEUR":            "fire",
+				"TPAOEUT":            "fight",
+				"-R":                 "{^er}",
+				"TPAOEUR/TPAOEU/TER": "firefighter",
+			},
+			oracles:  []splitOracle{pronunciations},
+			expected: map[string]string{"TPAOEUR/TPAOEUT/-R": "firefighter"},
+		},
+		{
+			name: "outline that doesn't line up with the syllables",
+			dict: map[string]string{
+				"TPHO":         "no",
+				"TAEUBL":       "table",
+				"TPHOE/TAEUBL": "notable",
+			},
+			oracles:  []splitOracle{pronunciations},
+			expected: map[string]string{},
+		},
+		{
+			name: "word no oracle knows",
+			dict: map[string]string{
+				"TKOG":      "dog",
+				"HOUS":      "house",
+				"TKOG/HOUZ": "doghouse",
+			},
+			oracles:  []splitOracle{&PronunciationDictionary{}},
+			expected: map[string]string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := newTestAugmentationContext(test.dict)
+			addCompounds(test.oracles, ctx)
+			if !reflect.DeepEqual(*ctx.additionalEntries, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, *ctx.additionalEntries)
+			}
+		})
+	}
+}
+
+func TestWordCompoundsPassTheFinalBoundaryCheck(t *testing.T) {
+	options := augmentationOptions{
+		casePolicy:  caseVariantPolicy{mode: caseVariantsOff},
+		strokeCosts: DefaultStrokeCostModel(),
+		minScore:    math.Inf(-1),
+		pronunciations: &PronunciationDictionary{pronunciations: map[string][][]string{
+			"doghouse": {strings.Fields("D AO1 G HH AW2 S")},
+		}},
+	}
+	dict := map[string]string{"TKOG": "dog", "HOUS": "house", "TKOG/HOUZ": "doghouse"}
+	ctx := augmentDictionary(dict, options, log.New(io.Discard, "", 0))
+	if (*ctx.additionalEntries)["TKOG/HOUS"] != "doghouse" {
+		t.Errorf("expected TKOG/HOUS for doghouse, got %v", *ctx.additionalEntries)
+	}
+}
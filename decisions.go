@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"slices"
+)
+
+const ruleReviewAccepted = "review-accepted"
+
+const rejectedInReview = "rejected in review"
+
+// ReviewDecisions are the outlines accepted and rejected with the review subcommand. Rejected outlines
+// are never generated again, accepted ones are kept even if the rules stop generating them, and
+// rejected rules aren't applied to the word again.
+type ReviewDecisions struct {
+	Accepted map[string]string `json:"accepted"`
+	Rejected map[string]string `json:"rejected"`
+	// translation -> rules
+	RejectedRules map[string][]string `json:"rejected_rules"`
+}
+
+func newReviewDecisions() *ReviewDecisions {
+	return &ReviewDecisions{
+		Accepted:      make(map[string]string),
+		Rejected:      make(map[string]string),
+		RejectedRules: make(map[string][]string),
+	}
+}
+
+// LoadReviewDecisions reads a decisions file, starting with no decisions if it doesn't exist yet
+func LoadReviewDecisions(path string) (*ReviewDecisions, error) {
+	decisions := newReviewDecisions()
+	contents, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return decisions, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(contents, decisions); err != nil {
+		return nil, err
+	}
+	// files edited by hand might leave a section out
+	if decisions.Accepted == nil {
+		decisions.Accepted = make(map[string]string)
+	}
+	if decisions.Rejected == nil {
+		decisions.Rejected = make(map[string]string)
+	}
+	if decisions.RejectedRules == nil {
+		decisions.RejectedRules = make(map[string][]string)
+	}
+	return decisions, nil
+}
+
+func (d *ReviewDecisions) save(path string) error {
+	contents, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, contents, 0644)
+}
+
+func (d *ReviewDecisions) accepts(key, value string) bool {
+	return d != nil && hasKey(key, &d.Accepted) && d.Accepted[key] == value
+}
+
+// rejects reports whether an outline or the rule generating it was rejected for a translation
+func (d *ReviewDecisions) rejects(key, value, rule string) bool {
+	if d == nil {
+		return false
+	}
+	if rejectedValue, ok := d.Rejected[key]; ok && rejectedValue == value {
+		return true
+	}
+	return slices.Contains(d.RejectedRules[value], rule)
+}
+
+func (d *ReviewDecisions) accept(key, value string) {
+	delete(d.Rejected, key)
+	d.Accepted[key] = value
+}
+
+func (d *ReviewDecisions) reject(key, value string) {
+	delete(d.Accepted, key)
+	d.Rejected[key] = value
+}
+
+func (d *ReviewDecisions) rejectRule(value, rule string) {
+	if !slices.Contains(d.RejectedRules[value], rule) {
+		d.RejectedRules[value] = append(d.RejectedRules[value], rule)
+	}
+}
+
+// addAcceptedEntries pins the accepted outlines before anything is generated, unless the source
+// dictionaries now use the outline themselves
+func addAcceptedEntries(ctx *augmentationContext) int {
+	if ctx.decisions == nil {
+		return 0
+	}
+	added := 0
+	for _, key := range sortedMapKeys(&ctx.decisions.Accepted) {
+		value := ctx.decisions.Accepted[key]
+		if hasKey(key, ctx.originalDictionary) {
+			continue
+		}
+		(*ctx.additionalEntries)[key] = value
+		ctx.alternativeCounts[value]++
+		ctx.provenance[key] = provenanceRecord{Outline: key, Translation: value, Rule: ruleReviewAccepted,
+			StrokeCost: ctx.strokeCosts.outlineCost(key)}
+		added++
+	}
+	return added
+}
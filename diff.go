@@ -0,0 +1,145 @@
+package main
+
+import (
+	"cmp"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+)
+
+const noProvenance = "(no provenance)"
+
+// retranslation is an outline whose translation changed
+type retranslation struct {
+	before string
+	after  string
+}
+
+type dictionaryDiff struct {
+	added        map[string]string
+	removed      map[string]string
+	retranslated map[string]retranslation
+}
+
+func diffDictionaries(before, after *map[string]string) dictionaryDiff {
+	diff := dictionaryDiff{
+		added:        make(map[string]string),
+		removed:      make(map[string]string),
+		retranslated: make(map[string]retranslation),
+	}
+	for key, value := range *after {
+		beforeValue, ok := (*before)[key]
+		if !ok {
+			diff.added[key] = value
+		} else if beforeValue != value {
+			diff.retranslated[key] = retranslation{before: beforeValue, after: value}
+		}
+	}
+	for key, value := range *before {
+		if !hasKey(key, after) {
+			diff.removed[key] = value
+		}
+	}
+	return diff
+}
+
+// ruleDiff is the part of a diff generated by one rule
+type ruleDiff struct {
+	rule                         string
+	lines                        []string
+	added, removed, retranslated int
+}
+
+func provenanceRule(key string, provenance map[string]provenanceRecord) string {
+	if record, ok := provenance[key]; ok && record.Rule != "" {
+		return record.Rule
+	}
+	return noProvenance
+}
+
+// groupDiffByRule groups added and retranslated outlines by the rule that generated them now and
+// removed outlines by the rule that generated them before
+func groupDiffByRule(diff dictionaryDiff, beforeProvenance, afterProvenance map[string]provenanceRecord) []ruleDiff {
+	groups := make(map[string]*ruleDiff)
+	group := func(rule string) *ruleDiff {
+		if _, ok := groups[rule]; !ok {
+			groups[rule] = &ruleDiff{rule: rule}
+		}
+		return groups[rule]
+	}
+	for _, key := range sortedMapKeys(&diff.added) {
+		g := group(provenanceRule(key, afterProvenance))
+		g.added++
+		g.lines = append(g.lines, fmt.Sprintf("+ %s -> %s", key, diff.added[key]))
+	}
+	for _, key := range sortedMapKeys(&diff.removed) {
+		g := group(provenanceRule(key, beforeProvenance))
+		g.removed++
+		g.lines = append(g.lines, fmt.Sprintf("- %s -> %s", key, diff.removed[key]))
+	}
+	retranslatedKeys := make([]string, 0, len(diff.retranslated))
+	for key := range diff.retranslated {
+		retranslatedKeys = append(retranslatedKeys, key)
+	}
+	slices.Sort(retranslatedKeys)
+	for _, key := range retranslatedKeys {
+		g := group(provenanceRule(key, afterProvenance))
+		g.retranslated++
+		g.lines = append(g.lines, fmt.Sprintf("~ %s -> %s (was %s)", key, diff.retranslated[key].after, diff.retranslated[key].before))
+	}
+
+	var result []ruleDiff
+	for _, g := range groups {
+		result = append(result, *g)
+	}
+	slices.SortFunc(result, func(a, b ruleDiff) int {
+		return cmp.Compare(a.rule, b.rule)
+	})
+	return result
+}
+
+func writeDiffReport(out io.Writer, diff dictionaryDiff, beforeProvenance, afterProvenance map[string]provenanceRecord) {
+	fmt.Fprintf(out, "%d added, %d removed, %d retranslated\n", len(diff.added), len(diff.removed), len(diff.retranslated))
+	for _, g := range groupDiffByRule(diff, beforeProvenance, afterProvenance) {
+		fmt.Fprintf(out, "\n%s: %d added, %d removed, %d retranslated\n", g.rule, g.added, g.removed, g.retranslated)
+		for _, line := range g.lines {
+			fmt.Fprintln(out, "  "+line)
+		}
+	}
+}
+
+func runDiff(args []string) {
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	beforeProvenancePath := flags.String("old_provenance", "", "optional provenance file of the old dictionary")
+	afterProvenancePath := flags.String("new_provenance", "", "optional provenance file of the new dictionary")
+	flags.Parse(args)
+
+	if flags.NArg() != 2 {
+		fmt.Println("Usage: lapwing_augmentor diff [--old_provenance <provenance>] [--new_provenance <provenance>] <old.json> <new.json>")
+		os.Exit(1)
+	}
+	before := make(map[string]string)
+	if err := readDictionaryInto(flags.Arg(0), before); err != nil {
+		fmt.Println("Error reading old dictionary:", err)
+		os.Exit(1)
+	}
+	after := make(map[string]string)
+	if err := readDictionaryInto(flags.Arg(1), after); err != nil {
+		fmt.Println("Error reading new dictionary:", err)
+		os.Exit(1)
+	}
+	beforeProvenance, err := readOptionalProvenance(*beforeProvenancePath)
+	if err != nil {
+		fmt.Println("Error reading provenance:", err)
+		os.Exit(1)
+	}
+	afterProvenance, err := readOptionalProvenance(*afterProvenancePath)
+	if err != nil {
+		fmt.Println("Error reading provenance:", err)
+		os.Exit(1)
+	}
+
+	writeDiffReport(os.Stdout, diffDictionaries(&before, &after), beforeProvenance, afterProvenance)
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"maps"
+	"testing"
+)
+
+func TestDiffDictionaries(t *testing.T) {
+	before := map[string]string{"KATS": "cats", "KATZ": "cats", "HAP/PEU": "happy"}
+	after := map[string]string{"KATS": "cats", "HAP/PEU": "hap pee", "HA/PAE": "happy"}
+
+	diff := diffDictionaries(&before, &after)
+	if !maps.Equal(diff.added, map[string]string{"HA/PAE": "happy"}) {
+		t.Fatalf("unexpected added outlines %v", diff.added)
+	}
+	if !maps.Equal(diff.removed, map[string]string{"KATZ": "cats"}) {
+		t.Fatalf("unexpected removed outlines %v", diff.removed)
+	}
+	if !maps.Equal(diff.retranslated, map[string]retranslation{"HAP/PEU": {before: "happy", after: "hap pee"}}) {
+		t.Fatalf("unexpected retranslated outlines %v", diff.retranslated)
+	}
+}
+
+func TestWriteDiffReport(t *testing.T) {
+	before := map[string]string{"KATZ": "cats", "HAP/PEU": "happy"}
+	after := map[string]string{"HAP/PEU": "hap pee", "HA/PAE": "happy", "TKOGS": "dogs"}
+	beforeProvenance := map[string]provenanceRecord{"KATZ": {Outline: "KATZ", Rule: ruleSZFold}}
+	afterProvenance := map[string]provenanceRecord{
+		"HA/PAE": {Outline: "HA/PAE", Rule: ruleAlternateSplit},
+		"TKOGS":  {Outline: "TKOGS", Rule: ruleSZFold},
+	}
+
+	var out bytes.Buffer
+	writeDiffReport(&out, diffDictionaries(&before, &after), beforeProvenance, afterProvenance)
+	want := `2 added, 1 removed, 1 retranslated
+
+(no provenance): 0 added, 0 removed, 1 retranslated
+  ~ HAP/PEU -> hap pee (was happy)
+
+alternate-split: 1 added, 0 removed, 0 retranslated
+  + HA/PAE -> happy
+
+sz-fold: 1 added, 1 removed, 0 retranslated
+  + TKOGS -> dogs
+  - KATZ -> cats
+`
+	if out.String() != want {
+		t.Fatalf("writeDiffReport() = %q, want %q", out.String(), want)
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+const rejectedExcluded = "matches an --exclude file"
+
+// entryFilter matches dictionary entries against the lines of --include and --exclude files:
+//
+//	outline:KAT/-S   an exact outline
+//	word:cats        an exact translation
+//	glob:*/KWRA*     a pattern over the outline's strokes, where * matches anything and \* is the asterisk key
+//	regex:^un        a regular expression over the translation
+//
+// Blank lines and lines starting with # are ignored.
+type entryFilter struct {
+	outlines map[string]bool
+	words    map[string]bool
+	globs    []*regexp.Regexp
+	regexes  []*regexp.Regexp
+}
+
+// LoadEntryFilter reads the filter files, returning nil if there are none
+func LoadEntryFilter(paths []string) (*entryFilter, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	filter := &entryFilter{outlines: make(map[string]bool), words: make(map[string]bool)}
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		scanner := bufio.NewScanner(file)
+		for lineNumber := 1; scanner.Scan(); lineNumber++ {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			if err := filter.add(line); err != nil {
+				file.Close()
+				return nil, fmt.Errorf("%s:%d: %w", path, lineNumber, err)
+			}
+		}
+		file.Close()
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return filter, nil
+}
+
+func (f *entryFilter) add(line string) error {
+	kind, pattern, ok := strings.Cut(line, ":")
+	if !ok || pattern == "" {
+		return fmt.Errorf("expected outline:, word:, glob: or regex: followed by a pattern, got %q", line)
+	}
+	switch kind {
+	case "outline":
+		f.outlines[pattern] = true
+	case "word":
+		f.words[pattern] = true
+	case "glob":
+		f.globs = append(f.globs, strokeGlobRegex(pattern))
+	case "regex":
+		regex, err := regexp.Compile(pattern)
+		if err != nil {
+			return err
+		}
+		f.regexes = append(f.regexes, regex)
+	default:
+		return fmt.Errorf("unknown pattern type %q", kind)
+	}
+	return nil
+}
+
+// strokeGlobRegex turns a glob over strokes into a regular expression. * matches any keys and
+// strokes, while \* matches the asterisk key itself.
+func strokeGlobRegex(glob string) *regexp.Regexp {
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch {
+		case glob[i] == '\\' && i+1 < len(glob) && glob[i+1] == '*':
+			pattern.WriteString(regexp.QuoteMeta("*"))
+			i++
+		case glob[i] == '*':
+			pattern.WriteString(".*")
+		default:
+			pattern.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+	}
+	pattern.WriteString("$")
+	return regexp.MustCompile(pattern.String())
+}
+
+func (f *entryFilter) matches(key, value string) bool {
+	if f == nil {
+		return false
+	}
+	if f.outlines[key] || f.words[value] {
+		return true
+	}
+	for _, glob := range f.globs {
+		if glob.MatchString(key) {
+			return true
+		}
+	}
+	for _, regex := range f.regexes {
+		if regex.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// augments reports whether a source dictionary entry should be augmented: it has to match the
+// include files, if there are any, and not match the exclude files
+func (ctx *augmentationContext) augments(key, value string) bool {
+	return (ctx.include == nil || ctx.include.matches(key, value)) && !ctx.exclude.matches(key, value)
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEntryFilter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "filter.txt")
+	contents := strings.Join([]string{
+		"# stop these from getting augmentations",
+		"outline:KAT/-S",
+		"word:possibilities",
+		"",
+		"glob:*/KWRA*",
+		`glob:TK\*`,
+		"regex:^un",
+	}, "\n")
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+	filter, err := LoadEntryFilter([]string{path})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		key   string
+		value string
+		want  bool
+	}{
+		{key: "KAT/-S", value: "cats", want: true},
+		{key: "KATS", value: "cats", want: false},
+		{key: "POS/PWEUL/TEUS", value: "possibilities", want: true},
+		{key: "SOE/KWRAL", value: "social", want: true},
+		{key: "SOE/KWRAL/-S", value: "socials", want: true},
+		{key: "KWRAL", value: "yall", want: false},
+		{key: "TK*", value: "{^ed}", want: true},
+		{key: "TK*S", value: "dee", want: false},
+		{key: "TKO", value: "do", want: false},
+		{key: "UPB/TKO", value: "undo", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := filter.matches(tt.key, tt.value); got != tt.want {
+				t.Fatalf("matches(%q, %q) = %v, want %v", tt.key, tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadEntryFilterErrors(t *testing.T) {
+	for _, line := range []string{"KAT/-S", "stroke:KAT", "regex:(", "word:"} {
+		path := filepath.Join(t.TempDir(), "filter.txt")
+		if err := os.WriteFile(path, []byte(line), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadEntryFilter([]string{path}); err == nil {
+			t.Fatalf("expected an error for %q", line)
+		}
+	}
+}
+
+func TestAugmentsWithIncludeAndExclude(t *testing.T) {
+	ctx := newTestAugmentationContext(map[string]string{"KAT/-S": "cats", "TKOG/-S": "dogs"})
+	ctx.include = &entryFilter{words: map[string]bool{"cats": true, "dogs": true}}
+	ctx.exclude = &entryFilter{outlines: map[string]bool{"TKOG/-S": true}}
+
+	if !ctx.augments("KAT/-S", "cats") || ctx.augments("TKOG/-S", "dogs") || ctx.augments("PWEURD/-S", "birds") {
+		t.Fatal("expected only cats to be augmented")
+	}
+	if addEntryIfNotPresent("TKOGS", "dogs", derivation{rule: ruleSZFold, source: "TKOG/-S"}, ctx) {
+		t.Fatal("expected outlines generated from an excluded outline to be rejected")
+	}
+	if !addEntryIfNotPresent("KATS", "cats", derivation{rule: ruleSZFold, source: "KAT/-S"}, ctx) {
+		t.Fatal("expected KATS to be added")
+	}
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"slices"
+	"strings"
+)
+
+const ruleDidNotApply = "the rule didn't apply to any outline of the word"
+
+// inflectedForm is a word made from a base word and a suffix of the suffix inventory
+type inflectedForm struct {
+	base   string
+	suffix string
+}
+
+// inflectionFamilies groups the words of the source dictionaries into a base word and the inflected
+// forms of it that are also words, like walk, walks, walked and walking
+func inflectionFamilies(dict *map[string]string, suffixes *suffixInventory) map[string][]string {
+	words := make(map[string]bool)
+	for _, value := range *dict {
+		if inflectableWordRegex.MatchString(value) {
+			words[value] = true
+		}
+	}
+	families := make(map[string][]string)
+	for word := range words {
+		for _, suffix := range sortedMapKeys(&suffixes.chords) {
+			for _, inflected := range inflect(word, suffixText(suffix)) {
+				if words[inflected] && inflected != word && !slices.Contains(families[word], inflected) {
+					families[word] = append(families[word], inflected)
+				}
+			}
+		}
+	}
+	for word := range families {
+		slices.Sort(families[word])
+	}
+	return families
+}
+
+func suffixText(suffix string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(suffix, "{^"), "}")
+}
+
+// familyRule is the rule an entry counts towards in its family: inflected entries count towards the
+// rule of the outline they were inflected from
+func (ctx *augmentationContext) familyRule(origin derivation) string {
+	seen := map[string]bool{}
+	for (origin.rule == ruleInflection || origin.rule == ruleInflectionFamily) && !seen[origin.source] {
+		seen[origin.source] = true
+		record, ok := ctx.provenance[origin.source]
+		if !ok {
+			break
+		}
+		origin = record.derivation()
+	}
+	return origin.rule
+}
+
+// fillInflectionFamilies adds base forms for generated inflected outlines by taking the suffix chord
+// off the last stroke, like WAUBGD (walked) -> WAUBG (walk). Inflected forms of generated base forms
+// are already added by addInflections.
+func fillInflectionFamilies(suffixes *suffixInventory, ctx *augmentationContext) int {
+	inflectedForms := make(map[string][]inflectedForm)
+	for base, members := range inflectionFamilies(ctx.originalDictionary, suffixes) {
+		for _, suffix := range sortedMapKeys(&suffixes.chords) {
+			for _, inflected := range inflect(base, suffixText(suffix)) {
+				if slices.Contains(members, inflected) {
+					inflectedForms[inflected] = append(inflectedForms[inflected], inflectedForm{base: base, suffix: suffix})
+				}
+			}
+		}
+	}
+	added := 0
+	for _, key := range ctx.frequencies.sortedKeys(ctx.additionalEntries) {
+		value := (*ctx.additionalEntries)[key]
+		strokes := strings.Split(key, "/")
+		last := strokeParts(strokes[len(strokes)-1])
+		if !last.Valid {
+			continue
+		}
+		for _, form := range inflectedForms[value] {
+			for _, chord := range suffixes.chords[form.suffix] {
+				if !strings.HasSuffix(last.Right, chord) {
+					continue
+				}
+				stripped := last
+				stripped.Right = strings.TrimSuffix(last.Right, chord)
+				if stripped.Left == "" && stripped.Vowels == "" && stripped.Right == "" {
+					continue
+				}
+				stroke, ok := validMergedStroke(stripped)
+				if !ok {
+					continue
+				}
+				baseKey := strings.Join(append(strokes[:len(strokes)-1:len(strokes)-1], stroke), "/")
+				if addEntryIfNotPresent(baseKey, form.base, derivation{rule: ruleInflectionFamily, source: key}, ctx) {
+					added++
+				}
+			}
+		}
+	}
+	return added
+}
+
+// blockedCandidate is an outline a rule generated for a word that was dropped
+type blockedCandidate struct {
+	Outline string `json:"outline"`
+	Reason  string `json:"reason"`
+}
+
+// familyGap is a member of a family that a rule applied to some other member of wasn't applied to
+type familyGap struct {
+	Word    string             `json:"word"`
+	Rule    string             `json:"rule"`
+	Reason  string             `json:"reason,omitempty"`
+	Blocked []blockedCandidate `json:"blocked,omitempty"`
+}
+
+// familyReport is a family whose members weren't all augmented by the same rules
+type familyReport struct {
+	Base    string      `json:"base"`
+	Members []string    `json:"members"`
+	Gaps    []familyGap `json:"gaps"`
+}
+
+// inflectionFamilyGaps finds the families where a rule generated outlines for some members but not
+// for others, with the reasons the candidates for the other members were dropped. Rejections have to
+// be recorded for the reasons to be known.
+func inflectionFamilyGaps(suffixes *suffixInventory, ctx *augmentationContext) []familyReport {
+	rules := make(map[string]map[string]bool)
+	for key, value := range *ctx.additionalEntries {
+		rule := ctx.familyRule(ctx.provenance[key].derivation())
+		if rule == "" || rule == ruleCaseVariant {
+			continue
+		}
+		if rules[value] == nil {
+			rules[value] = make(map[string]bool)
+		}
+		rules[value][rule] = true
+	}
+	type wordRule struct{ word, rule string }
+	blocked := make(map[wordRule][]blockedCandidate)
+	rejectedKeys := make([]string, 0, len(ctx.rejections))
+	for key := range ctx.rejections {
+		rejectedKeys = append(rejectedKeys, key)
+	}
+	slices.Sort(rejectedKeys)
+	for _, key := range rejectedKeys {
+		for _, rejection := range ctx.rejections[key] {
+			index := wordRule{rejection.translation, ctx.familyRule(rejection.origin)}
+			blocked[index] = append(blocked[index], blockedCandidate{Outline: key, Reason: rejection.reason})
+		}
+	}
+
+	families := inflectionFamilies(ctx.originalDictionary, suffixes)
+	var reports []familyReport
+	for _, base := range sortedMapKeys(&families) {
+		members := append([]string{base}, families[base]...)
+		familyRules := make(map[string]string)
+		for _, member := range members {
+			for rule := range rules[member] {
+				familyRules[rule] = rule
+			}
+		}
+		var gaps []familyGap
+		for _, rule := range sortedMapKeys(&familyRules) {
+			for _, member := range members {
+				if rules[member][rule] {
+					continue
+				}
+				gap := familyGap{Word: member, Rule: rule, Blocked: blocked[wordRule{member, rule}]}
+				if len(gap.Blocked) == 0 {
+					gap.Reason = ruleDidNotApply
+				}
+				gaps = append(gaps, gap)
+			}
+		}
+		if len(gaps) > 0 {
+			reports = append(reports, familyReport{Base: base, Members: members, Gaps: gaps})
+		}
+	}
+	return reports
+}
+
+func writeInflectionFamilyReport(path string, reports []familyReport) error {
+	contents, err := json.MarshalIndent(reports, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, contents, 0644)
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"io"
+	"log"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestInflectionFamilies(t *testing.T) {
+	dict := map[string]string{
+		"-S":        "{^s}",
+		"-D":        "{^ed}",
+		"-G":        "{^ing}",
+		"WAUBG":     "walk",
+		"WAUBGD":    "walked",
+		"WAUBG/-G":  "walking",
+		"KAER/REU":  "carry",
+		"KAER/AOED": "carried",
+		"TPHOUPB":   "noun",
+	}
+	families := inflectionFamilies(&dict, newSuffixInventory(&dict))
+	expected := map[string][]string{
+		"walk":  {"walked", "walking"},
+		"carry": {"carried"},
+	}
+	if !reflect.DeepEqual(families, expected) {
+		t.Errorf("expected %v, got %v", expected, families)
+	}
+}
+
+func TestFillInflectionFamilies(t *testing.T) {
+	ctx := newTestAugmentationContext(map[string]string{
+		"-D":     "{^ed}",
+		"WAUBG":  "walk",
+		"WAUBGD": "walked",
+	})
+	(*ctx.additionalEntries)["WA*UBGD"] = "walked"
+	ctx.provenance["WA*UBGD"] = provenanceRecord{Outline: "WA*UBGD", Translation: "walked", Rule: ruleAlternateSplit}
+
+	added := fillInflectionFamilies(newSuffixInventory(ctx.originalDictionary), ctx)
+	if added != 1 || (*ctx.additionalEntries)["WA*UBG"] != "walk" {
+		t.Errorf("expected WA*UBG to be added for walk, got %v", *ctx.additionalEntries)
+	}
+	if rule := ctx.familyRule(ctx.provenance["WA*UBG"].derivation()); rule != ruleAlternateSplit {
+		t.Errorf("expected WA*UBG to count towards %s, got %s", ruleAlternateSplit, rule)
+	}
+}
+
+func TestInflectionFamilyGaps(t *testing.T) {
+	ctx := newTestAugmentationContext(map[string]string{
+		"-D":       "{^ed}",
+		"-G":       "{^ing}",
+		"WAUBG":    "walk",
+		"WAUBGD":   "walked",
+		"WAUBG/-G": "walking",
+	})
+	ctx.rejections = make(map[string][]rejection)
+	(*ctx.additionalEntries)["WA*UBG"] = "walk"
+	ctx.provenance["WA*UBG"] = provenanceRecord{Outline: "WA*UBG", Translation: "walk", Rule: ruleAlternateSplit}
+	(*ctx.additionalEntries)["WA*UBGD"] = "walked"
+	ctx.provenance["WA*UBGD"] = provenanceRecord{Outline: "WA*UBGD", Translation: "walked", Rule: ruleInflection, Source: "WA*UBG"}
+	ctx.reject("WA*UBG/-G", "walking", derivation{rule: ruleAlternateSplit, source: "WAUBG/-G"}, rejectedWordBoundary)
+
+	reports := inflectionFamilyGaps(newSuffixInventory(ctx.originalDictionary), ctx)
+	expected := []familyReport{{
+		Base:    "walk",
+		Members: []string{"walk", "walked", "walking"},
+		Gaps: []familyGap{{
+			Word:    "walking",
+			Rule:    ruleAlternateSplit,
+			Blocked: []blockedCandidate{{Outline: "WA*UBG/-G", Reason: rejectedWordBoundary}},
+		}},
+	}}
+	if !reflect.DeepEqual(reports, expected) {
+		t.Errorf("expected %+v, got %+v", expected, reports)
+	}
+}
+
+func TestFilledBaseFormsAreInflected(t *testing.T) {
+	dict := map[string]string{
+		"-D":       "{^ed}",
+		"-S":       "{^s}",
+		"WAUK":     "walk",
+		"WAUKS":    "walks",
+		"WAUBG/-D": "walked",
+	}
+	options := augmentationOptions{
+		casePolicy:             caseVariantPolicy{mode: caseVariantsOff},
+		strokeCosts:            DefaultStrokeCostModel(),
+		minScore:               math.Inf(-1),
+		fillInflectionFamilies: true,
+	}
+	ctx := augmentDictionary(dict, options, log.New(io.Discard, "", 0))
+	// WAUBGD (walked) is folded, WAUBG (walk) is filled in from it and then gets WAUBGS (walks)
+	for key, value := range map[string]string{"WAUBGD": "walked", "WAUBG": "walk", "WAUBGS": "walks"} {
+		if (*ctx.additionalEntries)[key] != value {
+			t.Errorf("expected %s for %s, got %v", key, value, *ctx.additionalEntries)
+		}
+	}
+}
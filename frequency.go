@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// WordFrequencies ranks words from most to least common
+type WordFrequencies struct {
+	ranks map[string]int
+}
+
+// LoadWordFrequencies reads a word list with one word per line, either ordered from most to least
+// common or followed by a count, e.g. "the 23135851162". Lines starting with # are comments.
+func LoadWordFrequencies(path string) (*WordFrequencies, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	type wordCount struct {
+		word  string
+		count int
+		line  int
+	}
+	var counts []wordCount
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 0; scanner.Scan(); lineNumber++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		count := -1
+		if len(fields) > 1 {
+			if parsed, err := strconv.Atoi(fields[len(fields)-1]); err == nil {
+				count = parsed
+			}
+		}
+		counts = append(counts, wordCount{word: strings.ToLower(fields[0]), count: count, line: lineNumber})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// lists without counts are already in order
+	slices.SortStableFunc(counts, func(a, b wordCount) int {
+		if a.count != b.count {
+			return cmp.Compare(b.count, a.count)
+		}
+		return cmp.Compare(a.line, b.line)
+	})
+	frequencies := &WordFrequencies{ranks: make(map[string]int, len(counts))}
+	for _, wordCount := range counts {
+		if _, ok := frequencies.ranks[wordCount.word]; !ok {
+			frequencies.ranks[wordCount.word] = len(frequencies.ranks)
+		}
+	}
+	return frequencies, nil
+}
+
+// rank of a translation, lower is more common. Words missing from the list rank after every listed word.
+func (f *WordFrequencies) rank(value string) int {
+	if f == nil {
+		return 0
+	}
+	if rank, ok := f.ranks[strings.ToLower(strings.TrimSpace(value))]; ok {
+		return rank
+	}
+	return len(f.ranks)
+}
+
+// sortedKeys orders the keys of a dictionary so entries for more common words are processed first and
+// win outline conflicts, falling back to the shortest-first order of sortedMapKeys
+func (f *WordFrequencies) sortedKeys(dict *map[string]string) []string {
+	keys := sortedMapKeys(dict)
+	if f == nil {
+		return keys
+	}
+	slices.SortStableFunc(keys, func(a, b string) int {
+		return cmp.Compare(f.rank((*dict)[a]), f.rank((*dict)[b]))
+	})
+	return keys
+}
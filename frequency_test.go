@@ -0,0 +1,98 @@
+package main
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestLoadWordFrequencies(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name     string
+		contents string
+		want     []string
+	}{
+		{name: "ordered list", contents: "the\nof\n# comment\nand\n", want: []string{"the", "of", "and"}},
+		{name: "counts", contents: "and 10\nthe 30\nOf 20\n", want: []string{"the", "of", "and"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, "frequency.txt")
+			if err := os.WriteFile(path, []byte(tt.contents), 0644); err != nil {
+				t.Fatal(err)
+			}
+			frequencies, err := LoadWordFrequencies(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for wantRank, word := range tt.want {
+				if got := frequencies.rank(word); got != wantRank {
+					t.Fatalf("rank(%q) = %d, want %d", word, got, wantRank)
+				}
+			}
+			if got := frequencies.rank("missing"); got != len(tt.want) {
+				t.Fatalf("rank(missing) = %d, want %d", got, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestWordFrequenciesSortedKeys(t *testing.T) {
+	frequencies := &WordFrequencies{ranks: map[string]int{"the": 0, "possibility": 1}}
+	dict := map[string]string{
+		"A":             "a",
+		"POS/PWEUL/TEU": "possibility",
+		"-T":            "the",
+		"KAT":           "cat",
+	}
+	got := frequencies.sortedKeys(&dict)
+	want := []string{"-T", "POS/PWEUL/TEU", "A", "KAT"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("sortedKeys() = %q, want %q", got, want)
+	}
+
+	var missing *WordFrequencies
+	if got := missing.sortedKeys(&dict); !slices.Equal(got, sortedMapKeys(&dict)) {
+		t.Fatalf("sortedKeys() without frequencies = %q", got)
+	}
+}
+
+func TestAddEntryIfNotPresentContestedOutline(t *testing.T) {
+	originalDictionary := map[string]string{"KAT": "cat"}
+	additionalEntries := make(map[string]string)
+	ctx := &augmentationContext{
+		originalDictionary:     &originalDictionary,
+		additionalEntries:      &additionalEntries,
+		prefixTree:             NewPrefixTree(),
+		ignoredChordPatterns:   &map[string]bool{},
+		frequencies:            &WordFrequencies{ranks: map[string]int{"the": 0, "cats": 1}},
+		maxAlternativesPerWord: 1,
+		alternativeCounts:      make(map[string]int),
+		minScore:               math.Inf(-1),
+		provenance:             make(map[string]provenanceRecord),
+		strokeCosts:            DefaultStrokeCostModel(),
+	}
+
+	if !addEntryIfNotPresent("KATS", "cats", derivation{}, ctx) {
+		t.Fatal("expected KATS to be added")
+	}
+	if addEntryIfNotPresent("KATZ", "cats", derivation{}, ctx) {
+		t.Fatal("expected the alternative limit to reject KATZ")
+	}
+	if addEntryIfNotPresent("KATS", "rarer", derivation{}, ctx) {
+		t.Fatal("expected a less common word not to take over KATS")
+	}
+	if !addEntryIfNotPresent("KATS", "the", derivation{}, ctx) || additionalEntries["KATS"] != "the" {
+		t.Fatal("expected a more common word to take over KATS")
+	}
+	if !addEntryIfNotPresent("KATZ", "cats", derivation{}, ctx) {
+		t.Fatal("expected cats to have room for another outline after losing KATS")
+	}
+	if addEntryIfNotPresent("KAT", "the", derivation{}, ctx) {
+		t.Fatal("expected outlines from the source dictionaries never to be taken over")
+	}
+}
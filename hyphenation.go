@@ -0,0 +1,199 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"unicode"
+)
+
+// HyphenationPatterns is a Liang/TeX style hyphenation pattern set, e.g. hyph-en-us.tex
+type HyphenationPatterns struct {
+	patterns         map[string][]int
+	exceptions       map[string][]int
+	maxPatternLength int
+}
+
+// LoadHyphenationPatterns reads patterns like "1ba" or ".ach4" and exceptions like "as-so-ciate".
+// TeX files with \patterns{...} and \hyphenation{...} groups and % comments are supported,
+// as are the plain .pat.txt and .hyp.txt files.
+func LoadHyphenationPatterns(path string) (*HyphenationPatterns, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	hyphenation := &HyphenationPatterns{
+		patterns:   make(map[string][]int),
+		exceptions: make(map[string][]int),
+	}
+	for _, line := range strings.Split(string(contents), "\n") {
+		if commentIndex := strings.Index(line, "%"); commentIndex != -1 {
+			line = line[:commentIndex]
+		}
+		for _, token := range strings.Fields(line) {
+			// skip \patterns{, \hyphenation{ and the closing brace
+			if strings.HasPrefix(token, "\\") || token == "}" {
+				continue
+			}
+			token = strings.Trim(token, "{}")
+			if token == "" {
+				continue
+			}
+			if strings.Contains(token, "-") {
+				hyphenation.addException(token)
+			} else {
+				hyphenation.addPattern(token)
+			}
+		}
+	}
+	return hyphenation, nil
+}
+
+func (h *HyphenationPatterns) addPattern(pattern string) {
+	var letters []rune
+	values := []int{0}
+	for _, r := range pattern {
+		if r >= '0' && r <= '9' {
+			values[len(values)-1] = int(r - '0')
+			continue
+		}
+		letters = append(letters, unicode.ToLower(r))
+		values = append(values, 0)
+	}
+	h.patterns[string(letters)] = values
+	h.maxPatternLength = max(h.maxPatternLength, len(letters))
+}
+
+func (h *HyphenationPatterns) addException(exception string) {
+	var letters []rune
+	values := []int{0}
+	for _, r := range exception {
+		if r == '-' {
+			values[len(values)-1] = 1
+			continue
+		}
+		letters = append(letters, unicode.ToLower(r))
+		values = append(values, 0)
+	}
+	h.exceptions[string(letters)] = values
+}
+
+// hyphenationValues returns the Liang value of every position in word, where index i is the position
+// before the i-th letter. Odd values allow a hyphen, even values above zero forbid one.
+func (h *HyphenationPatterns) hyphenationValues(word string) []int {
+	word = strings.ToLower(word)
+	letters := []rune(word)
+	if values, ok := h.exceptions[word]; ok {
+		return values
+	}
+
+	dotted := append(append([]rune{'.'}, letters...), '.')
+	points := make([]int, len(dotted)+1)
+	for start := range dotted {
+		for end := start + 1; end <= len(dotted) && end-start <= h.maxPatternLength; end++ {
+			values, ok := h.patterns[string(dotted[start:end])]
+			if !ok {
+				continue
+			}
+			for offset, value := range values {
+				points[start+offset] = max(points[start+offset], value)
+			}
+		}
+	}
+	// drop the positions around the dots so index i lines up with the letters of word again
+	values := points[1 : len(letters)+2]
+	values[0] = 0
+	values[len(values)-1] = 0
+	return values
+}
+
+func (h *HyphenationPatterns) knowsWord(value string) bool {
+	return h != nil && orthographicWord(value) != ""
+}
+
+// orthographicWord lowercases a single word translation, returning "" for anything else
+func orthographicWord(value string) string {
+	word := strings.ToLower(strings.TrimSpace(value))
+	if word == "" || strings.ContainsFunc(word, func(r rune) bool { return r < 'a' || r > 'z' }) {
+		return ""
+	}
+	return word
+}
+
+type letterRange struct {
+	start int
+	end   int
+}
+
+func isVowelLetter(word string, i int) bool {
+	switch word[i] {
+	case 'a', 'e', 'i', 'o', 'u':
+		return true
+	case 'y':
+		// y is a consonant at the start of a word or syllable, as in "yes" or "beyond"
+		return i > 0 && !isVowelLetter(word, i-1)
+	}
+	return false
+}
+
+// vowelGroups finds the runs of vowel letters in word, ignoring a silent final e
+func vowelGroups(word string) []letterRange {
+	var groups []letterRange
+	for i := 0; i < len(word); i++ {
+		if !isVowelLetter(word, i) {
+			continue
+		}
+		if len(groups) > 0 && groups[len(groups)-1].end == i {
+			groups[len(groups)-1].end = i + 1
+		} else {
+			groups = append(groups, letterRange{start: i, end: i + 1})
+		}
+	}
+	lastIndex := len(word) - 1
+	if len(groups) > 1 && word[lastIndex] == 'e' && groups[len(groups)-1].start == lastIndex &&
+		!strings.HasSuffix(word, "le") {
+		groups = groups[:len(groups)-1]
+	}
+	return groups
+}
+
+// judgeSplit places every stroke boundary in the consonant cluster between two vowel groups of the
+// word, using the spelling of the next stroke's left hand to decide how many letters start it.
+// Boundaries at hyphenation points score a point and boundaries the patterns forbid reject the split.
+// Boundaries elsewhere in a cluster that has a hyphenation point lose a point.
+func (h *HyphenationPatterns) judgeSplit(value string, strokes []string) splitJudgement {
+	word := orthographicWord(value)
+	groups := vowelGroups(word)
+	if len(groups) != len(strokes) {
+		return splitJudgement{}
+	}
+
+	values := h.hyphenationValues(word)
+	judgement := splitJudgement{known: true}
+	for i := 1; i < len(groups); i++ {
+		cluster := word[groups[i-1].end:groups[i].start]
+		onset := leftBankSpelling(strokes[i])
+		if isGlider(strokes[i]) && !strings.HasSuffix(cluster, "y") {
+			onset = ""
+		}
+		onsetLength := min(len(onset), len(cluster))
+		boundary := groups[i].start - onsetLength
+
+		clusterHasHyphen := false
+		for position := groups[i-1].end; position <= groups[i].start; position++ {
+			if values[position]%2 == 1 {
+				clusterHasHyphen = true
+			}
+		}
+		boundaryValue := values[boundary]
+		switch {
+		case boundaryValue%2 == 1:
+			judgement.score++
+		case boundaryValue > 0:
+			return splitJudgement{rejected: true}
+		case clusterHasHyphen:
+			judgement.score--
+		}
+	}
+	return judgement
+}
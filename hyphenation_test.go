@@ -0,0 +1,68 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLoadHyphenationPatterns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hyph.tex")
+	contents := "% test patterns\n\\patterns{\n1ba\n.ach4\n}\n\\hyphenation{\nas-so-ciate\n}\n"
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+	hyphenation, err := LoadHyphenationPatterns(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := hyphenation.patterns["ba"]; !slices.Equal(got, []int{1, 0, 0}) {
+		t.Fatalf("pattern 1ba = %v, want [1 0 0]", got)
+	}
+	if got := hyphenation.patterns[".ach"]; !slices.Equal(got, []int{0, 0, 0, 0, 4}) {
+		t.Fatalf("pattern .ach4 = %v, want [0 0 0 0 4]", got)
+	}
+	if got := hyphenation.hyphenationValues("associate"); !slices.Equal(got, []int{0, 0, 1, 0, 1, 0, 0, 0, 0, 0}) {
+		t.Fatalf("hyphenationValues(associate) = %v", got)
+	}
+}
+
+func TestHyphenationValues(t *testing.T) {
+	hyphenation := &HyphenationPatterns{patterns: make(map[string][]int), exceptions: make(map[string][]int)}
+	hyphenation.addPattern("a1b")
+	hyphenation.addPattern("b2l")
+	got := hyphenation.hyphenationValues("table")
+	want := []int{0, 0, 1, 2, 0, 0}
+	if !slices.Equal(got, want) {
+		t.Fatalf("hyphenationValues(table) = %v, want %v", got, want)
+	}
+}
+
+func TestHyphenationJudgeSplit(t *testing.T) {
+	hyphenation := &HyphenationPatterns{patterns: make(map[string][]int), exceptions: make(map[string][]int)}
+	hyphenation.addPattern("s1t")
+	hyphenation.addPattern("2str")
+	hyphenation.addPattern("1bu")
+
+	tests := []struct {
+		name    string
+		strokes string
+		want    splitJudgement
+	}{
+		{name: "hyphenation points", strokes: "TKEUS/TREU/PWAOUT", want: splitJudgement{score: 2, known: true}},
+		{name: "forbidden position", strokes: "TKEU/STREU/PWAOUT", want: splitJudgement{rejected: true}},
+		{name: "away from hyphenation point", strokes: "TKEUST/REU/PWAOUT", want: splitJudgement{score: 0, known: true}},
+		{name: "vowel groups don't match strokes", strokes: "TKEUS/TRAOUT", want: splitJudgement{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := hyphenation.judgeSplit("distribute", strings.Split(tt.strokes, "/"))
+			if got != tt.want {
+				t.Fatalf("judgeSplit(%q) = %+v, want %+v", tt.strokes, got, tt.want)
+			}
+		})
+	}
+}
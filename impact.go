@@ -0,0 +1,202 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"slices"
+	"strings"
+)
+
+const (
+	notGeneratedFromNewBase = "no longer generated from the new base dictionary"
+	removedFromNewBase      = "the word is no longer in the new base dictionary"
+)
+
+// upstreamConflict is an augmentation whose outline the base dictionary now uses itself
+type upstreamConflict struct {
+	Outline             string `json:"outline"`
+	Translation         string `json:"translation"`
+	OfficialTranslation string `json:"official_translation"`
+}
+
+// droppedAugmentation is an augmentation a run against the new base dictionary no longer generates
+type droppedAugmentation struct {
+	Outline     string `json:"outline"`
+	Translation string `json:"translation"`
+	Reason      string `json:"reason"`
+}
+
+// impactReport describes what an update of the base dictionary does to an existing augmentation file
+type impactReport struct {
+	UpstreamAdded        int                   `json:"upstream_added"`
+	UpstreamRemoved      int                   `json:"upstream_removed"`
+	UpstreamRetranslated int                   `json:"upstream_retranslated"`
+	Conflicts            []upstreamConflict    `json:"conflicts"`
+	Dropped              []droppedAugmentation `json:"dropped"`
+	// outlines that are generated again because the base dictionary stopped using an outline they clashed with
+	Freed map[string]string `json:"freed"`
+	// translation -> outlines the new run generates for words that lost augmentations
+	Suggestions map[string][]string `json:"suggestions"`
+}
+
+// upstreamImpact compares an existing augmentation file with a run against the new base dictionary
+func upstreamImpact(oldBase, newBase, augmentations *map[string]string, ctx *augmentationContext) impactReport {
+	upstream := diffDictionaries(oldBase, newBase)
+	report := impactReport{
+		UpstreamAdded:        len(upstream.added),
+		UpstreamRemoved:      len(upstream.removed),
+		UpstreamRetranslated: len(upstream.retranslated),
+		Freed:                make(map[string]string),
+		Suggestions:          make(map[string][]string),
+	}
+
+	officialWords := make(map[string]bool, len(*newBase))
+	for _, value := range *newBase {
+		officialWords[value] = true
+	}
+	affectedWords := make(map[string]bool)
+	for _, key := range sortedMapKeys(augmentations) {
+		value := (*augmentations)[key]
+		if officialValue, ok := (*newBase)[key]; ok {
+			if officialValue != value {
+				report.Conflicts = append(report.Conflicts, upstreamConflict{Outline: key, Translation: value, OfficialTranslation: officialValue})
+				affectedWords[value] = true
+			}
+			continue
+		}
+		if (*ctx.additionalEntries)[key] == value {
+			continue
+		}
+		reason := notGeneratedFromNewBase
+		if rejection, ok := ctx.rejectionFor(key, value); ok {
+			reason = rejection.reason
+		} else if !officialWords[value] {
+			reason = removedFromNewBase
+		}
+		report.Dropped = append(report.Dropped, droppedAugmentation{Outline: key, Translation: value, Reason: reason})
+		affectedWords[value] = true
+	}
+
+	// entries freed up by outlines the base dictionary no longer uses
+	isStaleOfficialOutline := func(outline string) bool {
+		return hasKey(outline, oldBase) && (*oldBase)[outline] != (*newBase)[outline]
+	}
+	for key, value := range *ctx.additionalEntries {
+		if hasKey(key, augmentations) {
+			continue
+		}
+		if affectedWords[value] {
+			report.Suggestions[value] = append(report.Suggestions[value], key)
+		}
+		strokes := strings.Split(key, "/")
+		freed := isStaleOfficialOutline(key)
+		for i := 1; i < len(strokes) && !freed; i++ {
+			freed = isStaleOfficialOutline(strings.Join(strokes[:i], "/")) || isStaleOfficialOutline(strings.Join(strokes[i:], "/"))
+		}
+		if freed {
+			report.Freed[key] = value
+		}
+	}
+	for value := range report.Suggestions {
+		slices.SortFunc(report.Suggestions[value], func(a, b string) int {
+			return cmp.Or(cmp.Compare(len(a), len(b)), cmp.Compare(a, b))
+		})
+	}
+	return report
+}
+
+func writeImpactReport(out io.Writer, report impactReport) {
+	fmt.Fprintf(out, "Base dictionary: %d added, %d removed, %d retranslated outlines\n",
+		report.UpstreamAdded, report.UpstreamRemoved, report.UpstreamRetranslated)
+
+	fmt.Fprintf(out, "\n%d augmentations now conflict with official outlines\n", len(report.Conflicts))
+	for _, conflict := range report.Conflicts {
+		fmt.Fprintf(out, "  %s -> %s is now officially %s\n", conflict.Outline, conflict.Translation, conflict.OfficialTranslation)
+	}
+	fmt.Fprintf(out, "\n%d augmentations are no longer generated\n", len(report.Dropped))
+	for _, dropped := range report.Dropped {
+		fmt.Fprintf(out, "  %s -> %s: %s\n", dropped.Outline, dropped.Translation, dropped.Reason)
+	}
+	fmt.Fprintf(out, "\n%d outlines are safe again now that the base dictionary doesn't use an outline they clashed with\n", len(report.Freed))
+	for _, key := range sortedMapKeys(&report.Freed) {
+		fmt.Fprintf(out, "  %s -> %s\n", key, report.Freed[key])
+	}
+	fmt.Fprintf(out, "\nSuggested replacements for %d affected words\n", len(report.Suggestions))
+	for _, value := range sortedMapKeys(&report.Suggestions) {
+		fmt.Fprintf(out, "  %s: %s\n", value, strings.Join(report.Suggestions[value], ", "))
+	}
+}
+
+// readImpactBases reads the old and new base dictionaries, each followed by the personal source
+// dictionaries the augmentations are generated from as well
+func readImpactBases(oldBasePath, newBasePath string, sourceDictPaths []string) (oldBase, newBase map[string]string, err error) {
+	oldBase, newBase = make(map[string]string), make(map[string]string)
+	for _, base := range []struct {
+		path string
+		dict map[string]string
+	}{{oldBasePath, oldBase}, {newBasePath, newBase}} {
+		for _, path := range append([]string{base.path}, sourceDictPaths...) {
+			if err := readDictionaryInto(path, base.dict); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	return oldBase, newBase, nil
+}
+
+func runImpact(args []string) {
+	logger := log.New(os.Stderr, "LOG: ", log.LstdFlags|log.Lmicroseconds)
+	flags := flag.NewFlagSet("impact", flag.ExitOnError)
+	var sourceDictPaths stringList
+	flags.Var(&sourceDictPaths, "lapwing_source", "personal source dictionary path(s) to use on top of both base dictionaries")
+	oldBasePath := flags.String("old_base", "", "base dictionary the augmentations were generated from")
+	newBasePath := flags.String("new_base", "", "updated base dictionary")
+	augmentationsPath := flags.String("augmentations", "", "existing generated dictionary")
+	reportPath := flags.String("report", "", "optional path to write the report to as JSON")
+	augmentationFlags := registerAugmentationFlags(flags)
+	flags.Parse(args)
+
+	if *oldBasePath == "" || *newBasePath == "" || *augmentationsPath == "" {
+		fmt.Println("Usage: lapwing_augmentor impact --old_base <old lapwing-base.json> --new_base <new lapwing-base.json> --augmentations <augmentations> " +
+			"[--lapwing_source <source-dict> ...] [--report <report>]")
+		os.Exit(1)
+	}
+	options, err := augmentationFlags.load(logger)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	options.recordRejections = true
+
+	oldBase, newBase, err := readImpactBases(*oldBasePath, *newBasePath, sourceDictPaths)
+	if err != nil {
+		fmt.Println("Error reading dictionary:", err)
+		os.Exit(1)
+	}
+	augmentations := make(map[string]string)
+	if err := readDictionaryInto(*augmentationsPath, augmentations); err != nil {
+		fmt.Println("Error reading dictionary:", err)
+		os.Exit(1)
+	}
+
+	ctx := augmentDictionary(newBase, options, logger)
+	report := upstreamImpact(&oldBase, &newBase, &augmentations, ctx)
+	writeImpactReport(os.Stdout, report)
+
+	if *reportPath != "" {
+		contents, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			fmt.Println("Error marshalling JSON:", err)
+			os.Exit(1)
+		}
+		if err := os.WriteFile(*reportPath, contents, 0644); err != nil {
+			fmt.Println("Error writing report:", err)
+			os.Exit(1)
+		}
+	}
+}
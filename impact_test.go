@@ -0,0 +1,116 @@
+package main
+
+import (
+	"io"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestUpstreamImpact(t *testing.T) {
+	oldBase := map[string]string{
+		"-S":        "{^s}",
+		"-Z":        "{^s}",
+		"KAT":       "cat",
+		"KAT/-S":    "cats",
+		"TKOG":      "dog",
+		"TKOG/-S":   "dogs",
+		"HAP":       "hap",
+		"HAP/KWREU": "happy",
+		"HA/PEU":    "ha pea",
+	}
+	newBase := map[string]string{
+		"-S":        "{^s}",
+		"-Z":        "{^s}",
+		"KAT":       "cat",
+		"KAT/-S":    "cats",
+		"KATS":      "Kats",
+		"TKOG":      "dog",
+		"HAP":       "hap",
+		"HAP/KWREU": "happy",
+	}
+	augmentations := map[string]string{
+		"KATS":  "cats",
+		"KATZ":  "cats",
+		"TKOGS": "dogs",
+	}
+	options := augmentationOptions{
+		casePolicy:       caseVariantPolicy{mode: caseVariantsOff},
+		strokeCosts:      DefaultStrokeCostModel(),
+		minScore:         math.Inf(-1),
+		recordRejections: true,
+	}
+	ctx := augmentDictionary(newBase, options, log.New(io.Discard, "", 0))
+
+	report := upstreamImpact(&oldBase, &newBase, &augmentations, ctx)
+	if report.UpstreamAdded != 1 || report.UpstreamRemoved != 2 || report.UpstreamRetranslated != 0 {
+		t.Fatalf("unexpected upstream counts %+v", report)
+	}
+	wantConflicts := []upstreamConflict{{Outline: "KATS", Translation: "cats", OfficialTranslation: "Kats"}}
+	if !slices.Equal(report.Conflicts, wantConflicts) {
+		t.Fatalf("conflicts = %+v, want %+v", report.Conflicts, wantConflicts)
+	}
+	wantDropped := []droppedAugmentation{
+		// the new official KATS gets its own S/Z alternative
+		{Outline: "KATZ", Translation: "cats", Reason: `outline was already generated for "Kats"`},
+		{Outline: "TKOGS", Translation: "dogs", Reason: removedFromNewBase},
+	}
+	if !slices.Equal(report.Dropped, wantDropped) {
+		t.Fatalf("dropped = %+v, want %+v", report.Dropped, wantDropped)
+	}
+	if report.Freed["HA/PEU"] != "happy" {
+		t.Fatalf("expected HA/PEU to be freed, got %v", report.Freed)
+	}
+	if _, ok := report.Suggestions["happy"]; ok {
+		t.Fatal("happy didn't lose any augmentations so it shouldn't get suggestions")
+	}
+}
+
+func TestUpstreamImpactWithPersonalSources(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"old.json":      `{"-S": "{^s}", "KAT": "cat", "KAT/-S": "cats"}`,
+		"new.json":      `{"-S": "{^s}", "KAT": "cat", "KAT/-S": "cats", "TKOG": "dog"}`,
+		"personal.json": `{"PWHROG": "blog", "PWHROG/-S": "blogs"}`,
+	}
+	for name, contents := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	augmentations := map[string]string{"KATS": "cats", "PWHROGS": "blogs"}
+	options := augmentationOptions{
+		casePolicy:       caseVariantPolicy{mode: caseVariantsOff},
+		strokeCosts:      DefaultStrokeCostModel(),
+		minScore:         math.Inf(-1),
+		recordRejections: true,
+	}
+
+	tests := []struct {
+		name        string
+		sources     []string
+		wantDropped []droppedAugmentation
+	}{
+		{name: "base dictionaries only", wantDropped: []droppedAugmentation{{Outline: "PWHROGS", Translation: "blogs", Reason: removedFromNewBase}}},
+		{name: "with the personal dictionary", sources: []string{filepath.Join(dir, "personal.json")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldBase, newBase, err := readImpactBases(filepath.Join(dir, "old.json"), filepath.Join(dir, "new.json"), tt.sources)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := augmentDictionary(newBase, options, log.New(io.Discard, "", 0))
+			report := upstreamImpact(&oldBase, &newBase, &augmentations, ctx)
+			if report.UpstreamAdded != 1 || report.UpstreamRemoved != 0 {
+				t.Fatalf("unexpected upstream counts %+v", report)
+			}
+			if !slices.Equal(report.Dropped, tt.wantDropped) {
+				t.Fatalf("dropped = %+v, want %+v", report.Dropped, tt.wantDropped)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	inflectableWordRegex = regexp.MustCompile(`^[a-z]+$`)
+	// a single vowel before a final consonant that can be doubled, as in stop -> stopping
+	doublingEndingRegex = regexp.MustCompile(`(^|[^aeiou])[aeiou][b-df-hj-np-tvz]$`)
+)
+
+// inflect attaches a suffix to a word following English spelling rules the way Plover's orthography
+// rules do: y becomes i (carry -> carried), a silent e is dropped (make -> making), a final consonant
+// may be doubled (stop -> stopped) and s becomes es after a sibilant (box -> boxes). Doubling isn't
+// always right (visit -> visited), so both spellings are returned and the dictionary decides.
+func inflect(word, suffix string) []string {
+	if word == "" || suffix == "" {
+		return nil
+	}
+	last := word[len(word)-1]
+	switch {
+	case suffix == "s" && (strings.HasSuffix(word, "s") || strings.HasSuffix(word, "x") || strings.HasSuffix(word, "z") ||
+		strings.HasSuffix(word, "ch") || strings.HasSuffix(word, "sh")):
+		return []string{word + "es"}
+	case last == 'y' && len(word) > 1 && !isVowelLetter(word, len(word)-2):
+		if suffix == "s" {
+			return []string{word[:len(word)-1] + "ies"}
+		}
+		if suffix[0] != 'i' {
+			return []string{word[:len(word)-1] + "i" + suffix}
+		}
+	case last == 'e' && isVowelLetter(suffix, 0):
+		// see -> seeing keeps the e, agree -> agreed doesn't add another
+		if len(word) > 1 && word[len(word)-2] == 'e' {
+			return []string{word + strings.TrimPrefix(suffix, "e")}
+		}
+		return []string{word[:len(word)-1] + suffix}
+	case isVowelLetter(suffix, 0) && doublingEndingRegex.MatchString(word):
+		return []string{word + string(last) + suffix, word + suffix}
+	}
+	return []string{word + suffix}
+}
+
+// addInflections pairs generated outlines for a base form with the suffix chords from the suffix
+// inventory folded into their last stroke, for every inflected form that is a word in the source
+// dictionaries
+func addInflections(suffixes *suffixInventory, ctx *augmentationContext) int {
+	words := make(map[string]bool)
+	for _, value := range *ctx.originalDictionary {
+		words[value] = true
+	}
+	added := 0
+	for _, key := range ctx.frequencies.sortedKeys(ctx.additionalEntries) {
+		value := (*ctx.additionalEntries)[key]
+		if !inflectableWordRegex.MatchString(value) {
+			continue
+		}
+		strokes := strings.Split(key, "/")
+		last := strokeParts(strokes[len(strokes)-1])
+		if !last.Valid {
+			continue
+		}
+		for _, suffix := range sortedMapKeys(&suffixes.chords) {
+			for _, inflected := range inflect(value, suffixText(suffix)) {
+				if !words[inflected] {
+					continue
+				}
+				for _, chord := range suffixes.chords[suffix] {
+					merged := last
+					merged.Right += chord
+					stroke, ok := validMergedStroke(merged)
+					if !ok {
+						continue
+					}
+					inflectedKey := strings.Join(append(strokes[:len(strokes)-1:len(strokes)-1], stroke), "/")
+					if addEntryIfNotPresent(inflectedKey, inflected, derivation{rule: ruleInflection, source: key}, ctx) {
+						added++
+					}
+				}
+			}
+		}
+	}
+	return added
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInflect(t *testing.T) {
+	tests := []struct {
+		word     string
+		suffix   string
+		expected []string
+	}{
+		{"cat", "s", []string{"cats"}},
+		{"box", "s", []string{"boxes"}},
+		{"match", "s", []string{"matches"}},
+		{"carry", "s", []string{"carries"}},
+		{"carry", "ed", []string{"carried"}},
+		{"carry", "ing", []string{"carrying"}},
+		{"play", "ed", []string{"played"}},
+		{"make", "ing", []string{"making"}},
+		{"bake", "ed", []string{"baked"}},
+		{"see", "ing", []string{"seeing"}},
+		{"agree", "ed", []string{"agreed"}},
+		{"free", "er", []string{"freer"}},
+		{"stop", "ing", []string{"stopping", "stoping"}},
+		{"visit", "ed", []string{"visitted", "visited"}},
+		{"need", "ed", []string{"needed"}},
+		{"fix", "ing", []string{"fixing"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.word+"+"+test.suffix, func(t *testing.T) {
+			inflected := inflect(test.word, test.suffix)
+			if !reflect.DeepEqual(inflected, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, inflected)
+			}
+		})
+	}
+}
+
+func TestAddInflections(t *testing.T) {
+	ctx := newTestAugmentationContext(map[string]string{
+		"-S":         "{^s}",
+		"-D":         "{^ed}",
+		"-G":         "{^ing}",
+		"STOP":       "stop",
+		"STOPD":      "stopped",
+		"STOP/-G":    "stopping",
+		"KAER/REU":   "carry",
+		"KAER/RAOED": "carried",
+	})
+	(*ctx.additionalEntries)["STO*P"] = "stop"
+	(*ctx.additionalEntries)["KAE/REU"] = "carry"
+
+	added := addInflections(newSuffixInventory(ctx.originalDictionary), ctx)
+
+	expected := map[string]string{
+		"STO*P":    "stop",
+		"STO*PD":   "stopped",
+		"STO*PG":   "stopping",
+		"KAE/REU":  "carry",
+		"KAE/REUD": "carried",
+	}
+	if !reflect.DeepEqual(*ctx.additionalEntries, expected) {
+		t.Errorf("expected %v, got %v", expected, *ctx.additionalEntries)
+	}
+	if added != 3 {
+		t.Errorf("expected 3 inflected entries, got %d", added)
+	}
+	if record := ctx.provenance["STO*PD"]; record.Rule != ruleInflection || record.Source != "STO*P" {
+		t.Errorf("expected STO*PD to be inflected from STO*P, got %+v", record)
+	}
+}
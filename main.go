@@ -11,11 +11,13 @@ import (
 	"slices"
 	"sort"
 	"strings"
-	"unicode"
 )
 
 const (
 	properNameStrokeLengthLimit = 6
+	// the chords that start a stroke with a glide instead of a consonant
+	kwrGlideChord = "KWR"
+	wGlideChord   = "W"
 )
 
 func sortedMapKeys[V string | []string](dict *map[string]V) []string {
@@ -47,16 +49,63 @@ func (s *stringList) Set(value string) error {
 	return nil
 }
 
-func CapitalizeFirstLetter(s string) string {
-	if len(s) == 0 {
-		return s
-	}
-	runes := []rune(s)
-	runes[0] = unicode.ToUpper(runes[0])
-	return string(runes)
+// augmentationContext holds the dictionaries and settings every generated candidate is checked against
+type augmentationContext struct {
+	originalDictionary   *map[string]string
+	additionalEntries    *map[string]string
+	prefixTree           *PrefixTree
+	ignoredChordPatterns *map[string]bool
+	minAlignment         float64
+	// only set when vowel alternatives should be checked against syllable stress
+	vowelStress *PronunciationDictionary
+	// optional word list used to order entries and settle contested outlines
+	frequencies            *WordFrequencies
+	maxAlternativesPerWord int
+	alternativeCounts      map[string]int
+	// candidates scoring below minScore are dropped, see candidateScore
+	minScore   float64
+	provenance map[string]provenanceRecord
+	// alternatives harder to write than their source outline are marked, and dropped if filterHarderStrokes is set
+	strokeCosts         *StrokeCostModel
+	filterHarderStrokes bool
+	// why candidates were dropped, only kept when recording rejections
+	rejections map[string][]rejection
+	// outlines accepted and rejected in review
+	decisions *ReviewDecisions
+	// only source entries matching include, if set, are augmented, and nothing matching exclude is generated
+	include *entryFilter
+	exclude *entryFilter
+	// also treat W as a glide after O and U vowels, see isWGlider
+	wGlide bool
+	// add asterisk variants of outlines that belong to other words, see addAsteriskFallback
+	asteriskFallback bool
+	// where word + word compounds join their words, which isn't a word boundary conflict for them
+	compoundSeams map[string]compoundSeams
 }
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "prune":
+			runPrune(os.Args[2:])
+			return
+		case "misstrokes":
+			runMisstrokes(os.Args[2:])
+			return
+		case "review":
+			runReview(os.Args[2:])
+			return
+		case "diff":
+			runDiff(os.Args[2:])
+			return
+		case "impact":
+			runImpact(os.Args[2:])
+			return
+		case "propose":
+			runPropose(os.Args[2:])
+			return
+		}
+	}
 
 	logger := log.New(os.Stdout, "LOG: ", log.LstdFlags|log.Lmicroseconds)
 	var (
@@ -65,12 +114,26 @@ func main() {
 	)
 	flag.Var(&sourceDictPaths, "lapwing_source", "source dictionary path(s)")
 	flag.Var(&targetDictPaths, "output_target", "target dictionary path(s)")
+	augmentationFlags := registerAugmentationFlags(flag.CommandLine)
+	provenancePath := flag.String("provenance", "", "optional path to write the rule, source outline and score of every generated entry to")
+	compareWithPath := flag.String("compare_with", "", "optional previous output to list the added, removed and retranslated outlines against")
+	compareWithProvenancePath := flag.String("compare_with_provenance", "", "optional provenance file of the previous output, used to group removed outlines by rule")
+	familyReportPath := flag.String("inflection_family_report", "", "optional path to write the inflection families whose members weren't all augmented by the same rules to, with the reasons")
+	discardManualEdits := flag.Bool("discard_manual_edits", false, "overwrite the output targets instead of keeping the entries edited by hand since the last run")
 	flag.Parse()
 
 	if len(sourceDictPaths) == 0 || len(targetDictPaths) == 0 {
 		fmt.Println("Usage: lapwing_augmentor --lapwing_source <source-dict> [--lapwing_source <source-dict2> ...] --output_target <target-dict> [--output_target <target-dict2> ...]")
 		os.Exit(1)
 	}
+	options, err := augmentationFlags.load(logger)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	if *familyReportPath != "" {
+		options.recordRejections = true
+	}
 
 	// sourceDictPaths := []string{"../aerick-steno-dictionaries/lapwing-base.json"}
 	// targetDictPaths := []string{"lapwing-augmentations.json"}
@@ -79,23 +142,74 @@ func main() {
 
 	for _, sourceDictPath := range sourceDictPaths {
 		logger.Println("Reading in dictionary from ", sourceDictPath)
-		sourceContents, err := os.ReadFile(sourceDictPath)
-		if err != nil {
+		if err := readDictionaryInto(sourceDictPath, originalDictionary); err != nil {
 			fmt.Println("Error reading source dictionary:", err)
 			os.Exit(1)
 		}
-		var sourceJSON map[string]interface{}
+	}
 
-		if err := json.Unmarshal([]byte(sourceContents), &sourceJSON); err != nil {
-			fmt.Println("Error parsing JSON:", err)
+	logger.Println("Done reading in dictionary(s). Combined size:", len(originalDictionary))
+
+	// the previous output is usually the output target, so it has to be read before it's overwritten
+	previousEntries := make(map[string]string)
+	var previousProvenance map[string]provenanceRecord
+	if *compareWithPath != "" {
+		if err := readDictionaryInto(*compareWithPath, previousEntries); err != nil {
+			fmt.Println("Error reading previous output:", err)
 			os.Exit(1)
 		}
-		for key, value := range sourceJSON {
-			originalDictionary[key] = value.(string)
+		previousProvenance, err = readOptionalProvenance(*compareWithProvenancePath)
+		if err != nil {
+			fmt.Println("Error reading provenance:", err)
+			os.Exit(1)
 		}
 	}
 
-	logger.Println("Done reading in dictionary(s). Combined size:", len(originalDictionary))
+	ctx := augmentDictionary(originalDictionary, options, logger)
+	additionalEntries := *ctx.additionalEntries
+
+	// write out additionalEntries to file at targetDictPath. The first target as written, manual edits
+	// included, is what the provenance and --compare_with describe.
+	var output map[string]string
+	for i, targetPath := range targetDictPaths {
+		written, conflicts, seeded, err := writeOutputTarget(targetPath, additionalEntries, *discardManualEdits)
+		if err != nil {
+			fmt.Println("Error writing to target dictionary:", err)
+			os.Exit(1)
+		}
+		if i == 0 {
+			output = written
+		}
+		if seeded {
+			writeSeededSnapshotWarning(os.Stdout, targetPath)
+			continue
+		}
+		log.Println("Wrote", len(written), "additional entries to", targetPath)
+		writeMergeConflicts(os.Stdout, targetPath, conflicts)
+	}
+	if *provenancePath != "" {
+		if err := writeProvenance(*provenancePath, output, ctx); err != nil {
+			fmt.Println("Error writing provenance:", err)
+			os.Exit(1)
+		}
+		log.Println("Wrote provenance of", len(output), "entries to", *provenancePath)
+	}
+	if *familyReportPath != "" {
+		reports := inflectionFamilyGaps(newSuffixInventory(ctx.originalDictionary), ctx)
+		if err := writeInflectionFamilyReport(*familyReportPath, reports); err != nil {
+			fmt.Println("Error writing inflection family report:", err)
+			os.Exit(1)
+		}
+		log.Println("Wrote", len(reports), "inconsistent inflection families to", *familyReportPath)
+	}
+	if *compareWithPath != "" {
+		writeDiffReport(os.Stdout, diffDictionaries(&previousEntries, &output), previousProvenance, outputProvenance(output, ctx))
+	}
+
+}
+
+// augmentDictionary generates the additional entries for a dictionary
+func augmentDictionary(originalDictionary map[string]string, options augmentationOptions, logger *log.Logger) *augmentationContext {
 
 	prefixTree := NewPrefixTree()
 
@@ -107,6 +221,9 @@ func main() {
 
 	logger.Println("Done populating prefix tree")
 
+	suffixes := newSuffixInventory(&originalDictionary)
+	logger.Println("Found", len(suffixes.suffixes), "suffix strokes to fold")
+
 	additionalEntries := make(map[string]string)
 	kwrSuffixPattern := `^.*/KWR([^/]+)$`
 	kwrSuffixRegex := regexp.MustCompile(kwrSuffixPattern)
@@ -149,10 +266,19 @@ func main() {
 	suffixReplacements["PL/KWREU"] = []string{"/PHEU"}
 	suffixReplacements["F/KWREU"] = []string{"/TPEU"}
 	suffixReplacements["BG/KWREU"] = []string{"/KEU"}
-	suffixReplacements["S"] = []string{"Z"}
-	suffixReplacements["Z"] = []string{"S"}
-	suffixReplacements["-G"] = []string{"G"}
-	suffixReplacements["G"] = []string{"-G"}
+	// a suffix chord at the end of an outline can be swapped for the other chords of the same suffix,
+	// like S and Z for {^s}
+	for _, suffix := range sortedMapKeys(&suffixes.chords) {
+		for _, chord := range suffixes.chords[suffix] {
+			for _, other := range suffixes.chords[suffix] {
+				if other != chord {
+					suffixReplacements[chord] = append(suffixReplacements[chord], other)
+				}
+			}
+		}
+	}
+	suffixReplacements["-G"] = append(suffixReplacements["-G"], "G")
+	suffixReplacements["G"] = append(suffixReplacements["G"], "-G")
 	suffixReplacements["RBL"] = []string{"RB"}
 	suffixReplacements["/A"] = []string{"/A*"}
 
@@ -217,11 +343,42 @@ func main() {
 		"S-G": true,
 	}
 
-	vowelsDashes := `[AEOU\-*]+`
-	vowelDashRegex := regexp.MustCompile(vowelsDashes)
-	rightHandAfterS := regexp.MustCompile(`[DZ]`)
+	ctx := &augmentationContext{
+		originalDictionary:     &originalDictionary,
+		additionalEntries:      &additionalEntries,
+		prefixTree:             prefixTree,
+		ignoredChordPatterns:   &ignoredChordPatterns,
+		minAlignment:           options.minAlignment,
+		frequencies:            options.frequencies,
+		maxAlternativesPerWord: options.maxAlternativesPerWord,
+		alternativeCounts:      make(map[string]int),
+		minScore:               options.minScore,
+		provenance:             make(map[string]provenanceRecord),
+		strokeCosts:            options.strokeCosts,
+		filterHarderStrokes:    options.filterHarderStrokes,
+		include:                options.include,
+		exclude:                options.exclude,
+		wGlide:                 options.wGlide,
+		asteriskFallback:       options.asteriskFallback,
+	}
+	if options.recordRejections {
+		ctx.rejections = make(map[string][]rejection)
+	}
+	if options.decisions != nil {
+		ctx.decisions = options.decisions
+		logger.Println("Pinned", addAcceptedEntries(ctx), "entries accepted in review")
+	}
+	if options.stressAwareVowels {
+		ctx.vowelStress = options.pronunciations
+	}
+	// pronunciations are checked first, hyphenation patterns are the fallback for words without one
+	splitOracles := []splitOracle{options.pronunciations, options.hyphenation}
+	frequencies := options.frequencies
+	prefixes := newPrefixInventory(&originalDictionary)
+	logger.Println("Found", len(prefixes.prefixes), "prefix strokes to fold")
+
 	originalDictionaryIndex := 0
-	sortedOriginalDictionaryKeys := sortedMapKeys(&originalDictionary)
+	sortedOriginalDictionaryKeys := frequencies.sortedKeys(&originalDictionary)
 	for _, key := range sortedOriginalDictionaryKeys {
 		value := originalDictionary[key]
 
@@ -229,6 +386,9 @@ func main() {
 		if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") && strings.Contains(value, "|") {
 			continue
 		}
+		if !ctx.augments(key, value) {
+			continue
+		}
 
 		originalDictionaryIndex++
 		if originalDictionaryIndex%10000 == 0 {
@@ -244,25 +404,11 @@ func main() {
 				// remove strokeIndexStart to strokeIndexEnd
 				strokeOmitted = slices.Delete(strokeOmitted, strokeIndexStart, len(strokes))
 
-				addEntryIfNotPresent(strings.Join(strokeOmitted, "/"), value, &originalDictionary, &additionalEntries, prefixTree, &ignoredChordPatterns)
+				addEntryIfNotPresent(strings.Join(strokeOmitted, "/"), value, derivation{rule: ruleStrokeOmission, source: key}, ctx)
 
 			}
 		}
 
-		// generate proper name version of the entry by uppercasing the first letter and adding a pound sign
-		if !strings.HasPrefix(key, "#") && !strings.HasPrefix(value, "{") && !strings.HasPrefix(value, "=") {
-			upperCasedValue := CapitalizeFirstLetter(value)
-			keyWithPound := "#" + key
-			addEntryIfNotPresent(keyWithPound, upperCasedValue, &originalDictionary, &additionalEntries, prefixTree, &ignoredChordPatterns)
-		}
-
-		// now generate downcased versions of #-prefixed entries
-		if strings.HasPrefix(key, "#") && !strings.HasPrefix(value, "{") && !strings.HasPrefix(value, "=") {
-			downCasedValue := strings.ToLower(value)
-			keyWithoutPound := strings.TrimPrefix(key, "#")
-			addEntryIfNotPresent(keyWithoutPound, downCasedValue, &originalDictionary, &additionalEntries, prefixTree, &ignoredChordPatterns)
-		}
-
 		if len(strokes) > properNameStrokeLengthLimit && value[0] >= 'A' && value[0] <= 'Z' {
 			logger.Println("Skipping key", key, "value = ", value, "since it looks to be a proper name with > ",
 				properNameStrokeLengthLimit, " strokes and probably has no strokes worth generating")
@@ -270,40 +416,40 @@ func main() {
 		}
 
 		if len(strokes) >= 2 {
-			alternateStrokes := generateAlternateSyllableSplitStrokes(strokes, &originalDictionary, &additionalEntries, prefixTree, &ignoredChordPatterns)
+			alternateStrokes := generateAlternateSyllableSplitStrokes(strokes, ctx)
+			alternateStrokes = rankAlternateSplits(value, alternateStrokes, splitOracles)
 			for _, strokeSet := range alternateStrokes {
-				addEntryIfNotPresent(strings.Join(strokeSet, "/"), value, &originalDictionary, &additionalEntries, prefixTree, &ignoredChordPatterns)
+				addEntryIfNotPresent(strings.Join(strokeSet, "/"), value, derivation{rule: ruleAlternateSplit, source: key}, ctx)
 			}
 
 			// look for cases where we can safely remove KWR without creating word boundary errors
 			if strings.Contains(key, "/KWR") {
-				variations := generateKwrRemovedVariations(key, strokes, &originalDictionary)
+				variations := generateGlideRemovedVariations(key, strokes, &originalDictionary, kwrGlideChord)
 				for _, variation := range variations {
-					addEntryIfNotPresent(strings.Join(variation, "/"), value, &originalDictionary, &additionalEntries, prefixTree, &ignoredChordPatterns)
+					addEntryIfNotPresent(strings.Join(variation, "/"), value, derivation{rule: ruleKwrRemoval, source: key}, ctx)
+				}
+			}
+			if ctx.wGlide && strings.Contains(key, "/W") {
+				for _, variation := range generateGlideRemovedVariations(key, strokes, &originalDictionary, wGlideChord) {
+					addEntryIfNotPresent(strings.Join(variation, "/"), value, derivation{rule: ruleWGlideRemoval, source: key}, ctx)
 				}
 			}
 		}
 
-		generateSZVariationForKey(key, strokes, vowelDashRegex, rightHandAfterS, value, &originalDictionary, &additionalEntries, prefixTree, &ignoredChordPatterns)
-
-		addSuffixReplacements(suffixReplacementKeys, suffixReplacements, key, value, &originalDictionary, &additionalEntries, prefixTree, &ignoredChordPatterns)
-		addPrefixReplacements(prefixReplacementKeys, prefixReplacements, key, value, &originalDictionary, &additionalEntries, prefixTree, &ignoredChordPatterns)
-		addStringReplacements(stringReplacementKeys, stringReplacements, key, value, &originalDictionary, &additionalEntries, prefixTree, &ignoredChordPatterns)
-		addLongOReplacements(key, value, &originalDictionary, &additionalEntries, prefixTree, &ignoredChordPatterns)
-		addFinalEUToAOEReplacements(key, value, &originalDictionary, &additionalEntries, prefixTree, &ignoredChordPatterns)
-		addInitialKHToKPHReplacements(key, value, &originalDictionary, &additionalEntries, prefixTree, &ignoredChordPatterns)
-
-		// for strokes that end with e.g. "/-<letters>", see if we can fold that into the last stroke
-		lastStroke := strokes[len(strokes)-1]
-		if strings.HasPrefix(lastStroke, "-") {
-			newStroke := strings.Replace(lastStroke, "-", "", 1)
-			newKey := strings.TrimSuffix(key, "/"+lastStroke) + newStroke
-			// this will check if it's a valid steno stroke
-			addEntryIfNotPresent(newKey, value, &originalDictionary, &additionalEntries, prefixTree, &ignoredChordPatterns)
-			// now see if we can also fold in S/Z
-			keyStrokes := strings.Split(newKey, "/")
-			generateSZVariationForKey(newKey, keyStrokes, vowelDashRegex, rightHandAfterS, value, &originalDictionary, &additionalEntries, prefixTree, &ignoredChordPatterns)
-		}
+		addSuffixReplacements(suffixReplacementKeys, suffixReplacements, key, value, ctx)
+		addPrefixReplacements(prefixReplacementKeys, prefixReplacements, key, value, ctx)
+		addStringReplacements(stringReplacementKeys, stringReplacements, key, value, ctx)
+		addLongOReplacements(key, value, ctx)
+		addFinalEUToAOEReplacements(key, value, ctx)
+		addInitialKHToKPHReplacements(key, value, ctx)
+
+		// fold strokes like "/-<letters>" into the stroke before them and left bank only strokes into
+		// the stroke after them, then see if we can also fold in a suffix
+		addAdjacentStrokeFolds(key, value, ctx, func(newKey string) {
+			addSuffixFolds(newKey, value, suffixes, ctx)
+		})
+		addSuffixFolds(key, value, suffixes, ctx)
+		addPrefixFolds(key, value, prefixes, ctx)
 		kwrMatch := kwrSuffixRegex.FindStringSubmatch(key)
 		if kwrMatch != nil {
 			kwrSuffix := kwrMatch[1]
@@ -316,77 +462,63 @@ func main() {
 			// so that we don't mix KWREU and KWRAE/AOE in the same outline which is kind of confusing
 			if kwrSuffix == "EU" && !(strings.Contains(key, "/KWREU/") && (strings.Contains(value, "y-") || strings.Contains(value, "y "))) {
 				keyVariation1 := fmt.Sprintf("%sAOE", kwrPrefix)
-				addEntryIfNotPresent(keyVariation1, value, &originalDictionary, &additionalEntries, prefixTree, &ignoredChordPatterns)
+				if ctx.vowelStress.allowsVowelAlternative(value, key, keyVariation1) {
+					addEntryIfNotPresent(keyVariation1, value, derivation{rule: ruleKwreuVowel, source: key}, ctx)
+				}
 				keyVariation2 := fmt.Sprintf("%sAE", kwrPrefix)
-				addEntryIfNotPresent(keyVariation2, value, &originalDictionary, &additionalEntries, prefixTree, &ignoredChordPatterns)
+				if ctx.vowelStress.allowsVowelAlternative(value, key, keyVariation2) {
+					addEntryIfNotPresent(keyVariation2, value, derivation{rule: ruleKwreuVowel, source: key}, ctx)
+				}
 			}
 		}
 	}
 
 	additionalEntryIndex := 0
-	sortedAdditionalEntryKeys := sortedMapKeys(&additionalEntries)
+	sortedAdditionalEntryKeys := frequencies.sortedKeys(&additionalEntries)
 	for _, key := range sortedAdditionalEntryKeys {
 		value := additionalEntries[key]
 		additionalEntryIndex++
 		strokes := strings.Split(key, "/")
-		// generate proper name version of the entry by uppercasing the first letter and adding a pound sign
-		if !strings.HasPrefix(key, "#") {
-			upperCasedValue := CapitalizeFirstLetter(value)
-			keyWithPound := "#" + key
-			addEntryIfNotPresent(keyWithPound, upperCasedValue, &originalDictionary, &additionalEntries, prefixTree, &ignoredChordPatterns)
-		}
-		// now generate downcased versions of #-prefixed entries
-		if strings.HasPrefix(key, "#") && !strings.HasPrefix(value, "{") && !strings.HasPrefix(value, "=") {
-			downCasedValue := strings.ToLower(value)
-			keyWithoutPound := strings.TrimPrefix(key, "#")
-			addEntryIfNotPresent(keyWithoutPound, downCasedValue, &originalDictionary, &additionalEntries, prefixTree, &ignoredChordPatterns)
-		}
 		if len(strokes) >= 2 {
 			// see if we can generate KWR removed variations on additional entries we just generated
 			if strings.Contains(key, "/KWR") {
 
-				variations := generateKwrRemovedVariations(key, strokes, &originalDictionary)
+				variations := generateGlideRemovedVariations(key, strokes, &originalDictionary, kwrGlideChord)
 				for _, variation := range variations {
-					addEntryIfNotPresent(strings.Join(variation, "/"), value, &originalDictionary, &additionalEntries, prefixTree, &ignoredChordPatterns)
+					addEntryIfNotPresent(strings.Join(variation, "/"), value, derivation{rule: ruleKwrRemoval, source: key}, ctx)
+				}
+			}
+			if ctx.wGlide && strings.Contains(key, "/W") {
+				for _, variation := range generateGlideRemovedVariations(key, strokes, &originalDictionary, wGlideChord) {
+					addEntryIfNotPresent(strings.Join(variation, "/"), value, derivation{rule: ruleWGlideRemoval, source: key}, ctx)
 				}
 			}
 		}
 		// see if we can generate suffix variations of generated additional entries
-		addSuffixReplacements(suffixReplacementKeys, suffixReplacements, key, value, &originalDictionary, &additionalEntries, prefixTree, &ignoredChordPatterns)
-		addPrefixReplacements(prefixReplacementKeys, prefixReplacements, key, value, &originalDictionary, &additionalEntries, prefixTree, &ignoredChordPatterns)
-		addStringReplacements(stringReplacementKeys, stringReplacements, key, value, &originalDictionary, &additionalEntries, prefixTree, &ignoredChordPatterns)
-		addLongOReplacements(key, value, &originalDictionary, &additionalEntries, prefixTree, &ignoredChordPatterns)
-		addFinalEUToAOEReplacements(key, value, &originalDictionary, &additionalEntries, prefixTree, &ignoredChordPatterns)
-		addInitialKHToKPHReplacements(key, value, &originalDictionary, &additionalEntries, prefixTree, &ignoredChordPatterns)
+		addSuffixReplacements(suffixReplacementKeys, suffixReplacements, key, value, ctx)
+		addPrefixReplacements(prefixReplacementKeys, prefixReplacements, key, value, ctx)
+		addStringReplacements(stringReplacementKeys, stringReplacements, key, value, ctx)
+		addLongOReplacements(key, value, ctx)
+		addFinalEUToAOEReplacements(key, value, ctx)
+		addInitialKHToKPHReplacements(key, value, ctx)
 	}
 
 	// one last time
 	additionalEntryIndex = 0
-	sortedAdditionalEntryKeys = sortedMapKeys(&additionalEntries)
+	sortedAdditionalEntryKeys = frequencies.sortedKeys(&additionalEntries)
 	for _, key := range sortedAdditionalEntryKeys {
 		value := additionalEntries[key]
 		additionalEntryIndex++
 		if additionalEntryIndex%1000 == 0 {
 			logger.Println("Processed", additionalEntryIndex, "/", len(sortedAdditionalEntryKeys), "additional entries (alternate splits)")
 		}
-		// generate proper name version of the entry by uppercasing the first letter and adding a pound sign
-		if !strings.HasPrefix(key, "#") {
-			upperCasedValue := CapitalizeFirstLetter(value)
-			keyWithPound := "#" + key
-			addEntryIfNotPresent(keyWithPound, upperCasedValue, &originalDictionary, &additionalEntries, prefixTree, &ignoredChordPatterns)
-		}
-		// now generate downcased versions of #-prefixed entries
-		if strings.HasPrefix(key, "#") && !strings.HasPrefix(value, "{") && !strings.HasPrefix(value, "=") {
-			downCasedValue := strings.ToLower(value)
-			keyWithoutPound := strings.TrimPrefix(key, "#")
-			addEntryIfNotPresent(keyWithoutPound, downCasedValue, &originalDictionary, &additionalEntries, prefixTree, &ignoredChordPatterns)
-		}
 		strokes := strings.Split(key, "/")
 		if len(strokes) >= 2 {
 			// now try generating alternate syllabic splits on previously added entries
-			alternateStrokes := generateAlternateSyllableSplitStrokes(strokes, &originalDictionary, &additionalEntries, prefixTree, &ignoredChordPatterns)
+			alternateStrokes := generateAlternateSyllableSplitStrokes(strokes, ctx)
+			alternateStrokes = rankAlternateSplits(value, alternateStrokes, splitOracles)
 			for _, strokeSet := range alternateStrokes {
-				addEntryIfNotPresent(strings.Join(strokeSet, "/"), value, &originalDictionary, &additionalEntries, prefixTree, &ignoredChordPatterns)
+				addEntryIfNotPresent(strings.Join(strokeSet, "/"), value, derivation{rule: ruleAlternateSplit, source: key}, ctx)
 			}
 		}
 	}
@@ -396,44 +528,29 @@ func main() {
 	// this comes about when we take a word like "synovia" which lapwing has as SEU/TPOEF/KWRA
 	// we move the TP over to the right hand to give SEUB/OEF/KWRA which is fine but we should also generate SEUB/KWROEF/KWRA
 	additionalEntryIndex = 0
-	sortedAdditionalEntryKeys = sortedMapKeys(&additionalEntries)
+	sortedAdditionalEntryKeys = frequencies.sortedKeys(&additionalEntries)
 	for _, key := range sortedAdditionalEntryKeys {
 		additionalEntryIndex++
 		if additionalEntryIndex%1000 == 0 {
 			logger.Println("Processed", additionalEntryIndex, "/", len(sortedAdditionalEntryKeys), "additional entries (KWR addition)")
 		}
 		value := additionalEntries[key]
-		// generate proper name version of the entry by uppercasing the first letter and adding a pound sign
-		if !strings.HasPrefix(key, "#") {
-			upperCasedValue := CapitalizeFirstLetter(value)
-			keyWithPound := "#" + key
-			addEntryIfNotPresent(keyWithPound, upperCasedValue, &originalDictionary, &additionalEntries, prefixTree, &ignoredChordPatterns)
-		}
-		// now generate downcased versions of #-prefixed entries
-		if strings.HasPrefix(key, "#") && !strings.HasPrefix(value, "{") && !strings.HasPrefix(value, "=") {
-			downCasedValue := strings.ToLower(value)
-			keyWithoutPound := strings.TrimPrefix(key, "#")
-			addEntryIfNotPresent(keyWithoutPound, downCasedValue, &originalDictionary, &additionalEntries, prefixTree, &ignoredChordPatterns)
-		}
 		strokes := strings.Split(key, "/")
 		if len(strokes) >= 2 {
-			kwrAddedStrokes := make([]string, len(strokes))
-			copy(kwrAddedStrokes, strokes)
-			for i, stroke := range strokes {
-				startsWithVowel := strings.HasPrefix(stroke, "A") || strings.HasPrefix(stroke, "E") ||
-					strings.HasPrefix(stroke, "O") || strings.HasPrefix(stroke, "U")
-				// only replace second stroke or later
-				if i > 0 && startsWithVowel {
-					kwrAddedStrokes[i] = "KWR" + kwrAddedStrokes[i]
+			for _, variation := range generateGlideAddedVariations(key, strokes, &originalDictionary, kwrGlideChord, options.maxKwrInsertionSubsets) {
+				addEntryIfNotPresent(strings.Join(variation, "/"), value, derivation{rule: ruleKwrAddition, source: key}, ctx)
+			}
+			if ctx.wGlide {
+				for _, variation := range generateGlideAddedVariations(key, strokes, &originalDictionary, wGlideChord, options.maxWInsertionSubsets) {
+					addEntryIfNotPresent(strings.Join(variation, "/"), value, derivation{rule: ruleWGlideAddition, source: key}, ctx)
 				}
 			}
-			addEntryIfNotPresent(strings.Join(kwrAddedStrokes, "/"), value, &originalDictionary, &additionalEntries, prefixTree, &ignoredChordPatterns)
 		}
 	}
 
 	// try to find multi stroke entries we can partially brief by omitting partial strokes
 	additionalEntryIndex = 0
-	sortedAdditionalEntryKeys = sortedMapKeys(&additionalEntries)
+	sortedAdditionalEntryKeys = frequencies.sortedKeys(&additionalEntries)
 	for _, key := range sortedAdditionalEntryKeys {
 		additionalEntryIndex++
 		if additionalEntryIndex%1000 == 0 {
@@ -448,60 +565,61 @@ func main() {
 				// remove strokeIndexStart to strokeIndexEnd
 				strokeOmitted = slices.Delete(strokeOmitted, strokeIndexStart, len(strokes))
 
-				addEntryIfNotPresent(strings.Join(strokeOmitted, "/"), additionalEntries[key], &originalDictionary, &additionalEntries, prefixTree, &ignoredChordPatterns)
+				addEntryIfNotPresent(strings.Join(strokeOmitted, "/"), additionalEntries[key], derivation{rule: ruleStrokeOmission, source: key}, ctx)
 			}
 		}
 	}
 
 	// do a final pass for stroke replacements that may have been generated by previous augmentation passes
 	additionalEntryIndex = 0
-	sortedAdditionalEntryKeys = sortedMapKeys(&additionalEntries)
+	sortedAdditionalEntryKeys = frequencies.sortedKeys(&additionalEntries)
 	for _, key := range sortedAdditionalEntryKeys {
 		additionalEntryIndex++
 		if additionalEntryIndex%1000 == 0 {
 			logger.Println("Processed", additionalEntryIndex, "/", len(sortedAdditionalEntryKeys), "additional entries (stroke replacements)")
 		}
-		addLongOReplacements(key, additionalEntries[key], &originalDictionary, &additionalEntries, prefixTree, &ignoredChordPatterns)
-		addFinalEUToAOEReplacements(key, additionalEntries[key], &originalDictionary, &additionalEntries, prefixTree, &ignoredChordPatterns)
-		addInitialKHToKPHReplacements(key, additionalEntries[key], &originalDictionary, &additionalEntries, prefixTree, &ignoredChordPatterns)
+		addLongOReplacements(key, additionalEntries[key], ctx)
+		addFinalEUToAOEReplacements(key, additionalEntries[key], ctx)
+		addInitialKHToKPHReplacements(key, additionalEntries[key], ctx)
 	}
 
+	// join the outlines of the parts of compound words
+	logger.Println("Added", addCompounds(splitOracles, ctx), "compound entries")
+
+	// base forms are filled in first so their other inflected forms are added below too
+	if options.fillInflectionFamilies {
+		logger.Println("Added", fillInflectionFamilies(suffixes, ctx), "base forms of inflected entries")
+	}
+	// add -s, -ed, -ing and so on to generated outlines for words whose inflected forms are in the source dictionaries
+	logger.Println("Added", addInflections(suffixes, ctx), "inflected entries")
+
+	// add proper name versions of entries by uppercasing and adding a pound sign, and downcased versions of #-prefixed entries
+	caseVariantCount := addCaseVariants(options.casePolicy, ctx)
+	logger.Println("Added", caseVariantCount, "case variants")
+
 	// do a final check of additional entries for valid word boundaries due to weird issues with order of addition
 	additionalEntryIndex = 0
-	sortedAdditionalEntryKeys = sortedMapKeys(&additionalEntries)
+	sortedAdditionalEntryKeys = frequencies.sortedKeys(&additionalEntries)
 	for _, key := range sortedAdditionalEntryKeys {
 		additionalEntryIndex++
 		if additionalEntryIndex%10000 == 0 {
 			logger.Println("Processed", additionalEntryIndex, "/", len(sortedAdditionalEntryKeys), "additional entries for final conflicting word boundaries")
 		}
 		strokes := strings.Split(key, "/")
-		if len(strokes) >= 2 {
-			if !validWordBoundaries(strokes, &originalDictionary, &additionalEntries, prefixTree, &ignoredChordPatterns) {
+		if len(strokes) >= 2 && !ctx.decisions.accepts(key, additionalEntries[key]) {
+			if !validWordBoundaries(strokes, ctx.originalDictionary, ctx.additionalEntries, ctx.prefixTree, ctx.ignoredChordPatterns, ctx.allowedSplits(key, additionalEntries[key])...) {
 				logger.Println("Removing", key, "due to conflicting word boundaries")
+				ctx.reject(key, additionalEntries[key], ctx.provenance[key].derivation(), rejectedFinalWordBoundary)
 				delete(additionalEntries, key)
 			}
 		}
 	}
 	log.Println("Added", len(additionalEntries), "additional entries overall after checking for conflicting word boundaries")
 
-	// write out additionalEntries to file at targetDictPath
-	contents, err := json.MarshalIndent(additionalEntries, "", "  ")
-	if err != nil {
-		fmt.Println("Error marshalling JSON:", err)
-		os.Exit(1)
-	}
-	for _, targetPath := range targetDictPaths {
-		if err := os.WriteFile(targetPath, contents, 0644); err != nil {
-			fmt.Println("Error writing to target dictionary:", err)
-			os.Exit(1)
-		}
-		log.Println("Wrote", len(additionalEntries), "additional entries to", targetPath)
-	}
-
+	return ctx
 }
 
-func addPrefixReplacements(suffixReplacementKeys []string, prefixReplacements map[string][]string, key string, value string, originalDictionary *map[string]string,
-	additionalEntries *map[string]string, prefixTree *PrefixTree, ignoredChordPatterns *map[string]bool) {
+func addPrefixReplacements(suffixReplacementKeys []string, prefixReplacements map[string][]string, key string, value string, ctx *augmentationContext) {
 
 	for _, replacedSuffix := range suffixReplacementKeys {
 		replacements := prefixReplacements[replacedSuffix]
@@ -509,15 +627,14 @@ func addPrefixReplacements(suffixReplacementKeys []string, prefixReplacements ma
 			for _, replacement := range replacements {
 				newKey := replacement + strings.TrimPrefix(key, replacedSuffix)
 				newKey = strings.ReplaceAll(newKey, "//", "/")
-				addEntryIfNotPresent(newKey, value, originalDictionary, additionalEntries, prefixTree, ignoredChordPatterns)
+				addEntryIfNotPresent(newKey, value, derivation{rule: rulePrefixReplacement, source: key}, ctx)
 			}
 			break
 		}
 	}
 }
 
-func addSuffixReplacements(prefixReplacementKeys []string, suffixReplacements map[string][]string, key string, value string, originalDictionary *map[string]string,
-	additionalEntries *map[string]string, prefixTree *PrefixTree, ignoredChordPatterns *map[string]bool) {
+func addSuffixReplacements(prefixReplacementKeys []string, suffixReplacements map[string][]string, key string, value string, ctx *augmentationContext) {
 	for _, replacedSuffix := range prefixReplacementKeys {
 		replacements := suffixReplacements[replacedSuffix]
 		if strings.HasSuffix(key, replacedSuffix) {
@@ -527,7 +644,10 @@ func addSuffixReplacements(prefixReplacementKeys []string, suffixReplacements ma
 			for _, replacement := range replacements {
 				newKey := strings.TrimSuffix(key, replacedSuffix) + replacement
 				newKey = strings.ReplaceAll(newKey, "//", "/")
-				addEntryIfNotPresent(newKey, value, originalDictionary, additionalEntries, prefixTree, ignoredChordPatterns)
+				if !ctx.vowelStress.allowsVowelAlternative(value, key, newKey) {
+					continue
+				}
+				addEntryIfNotPresent(newKey, value, derivation{rule: ruleSuffixReplacement, source: key}, ctx)
 			}
 			break
 		}
@@ -539,25 +659,26 @@ func suffixReplacementHasBareStem(key, replacedSuffix string) bool {
 	return strings.Trim(stem, "#-") == ""
 }
 
-func addStringReplacements(replacementKeys []string, replacements map[string][]string, key string, value string, originalDictionary *map[string]string,
-	additionalEntries *map[string]string, prefixTree *PrefixTree, ignoredChordPatterns *map[string]bool) {
+func addStringReplacements(replacementKeys []string, replacements map[string][]string, key string, value string, ctx *augmentationContext) {
 	for _, replacedKey := range replacementKeys {
 		replacements := replacements[replacedKey]
 		if strings.Contains(key, replacedKey) {
 			for _, replacement := range replacements {
 				newKey := strings.ReplaceAll(key, replacedKey, replacement)
 				newKey = strings.ReplaceAll(newKey, "//", "/")
-				addEntryIfNotPresent(newKey, value, originalDictionary, additionalEntries, prefixTree, ignoredChordPatterns)
+				if !ctx.vowelStress.allowsVowelAlternative(value, key, newKey) {
+					continue
+				}
+				addEntryIfNotPresent(newKey, value, derivation{rule: ruleStringReplacement, source: key}, ctx)
 			}
 		}
 	}
 }
 
-func addLongOReplacements(key, value string, originalDictionary *map[string]string,
-	additionalEntries *map[string]string, prefixTree *PrefixTree, ignoredChordPatterns *map[string]bool) {
+func addLongOReplacements(key, value string, ctx *augmentationContext) {
 	newKey, changed := longOReplacementKey(key)
 	if changed {
-		addEntryIfNotPresent(newKey, value, originalDictionary, additionalEntries, prefixTree, ignoredChordPatterns)
+		addEntryIfNotPresent(newKey, value, derivation{rule: ruleLongO, source: key}, ctx)
 	}
 }
 
@@ -589,11 +710,10 @@ func longOReplacementStroke(stroke string) (string, bool) {
 	return parts.Left + "OE" + parts.Right, true
 }
 
-func addFinalEUToAOEReplacements(key, value string, originalDictionary *map[string]string,
-	additionalEntries *map[string]string, prefixTree *PrefixTree, ignoredChordPatterns *map[string]bool) {
+func addFinalEUToAOEReplacements(key, value string, ctx *augmentationContext) {
 	newKey, changed := finalEUToAOEReplacementKey(key)
-	if changed {
-		addEntryIfNotPresent(newKey, value, originalDictionary, additionalEntries, prefixTree, ignoredChordPatterns)
+	if changed && ctx.vowelStress.allowsVowelAlternative(value, key, newKey) {
+		addEntryIfNotPresent(newKey, value, derivation{rule: ruleFinalEUToAOE, source: key}, ctx)
 	}
 }
 
@@ -623,11 +743,10 @@ func finalEUToAOEReplacementStroke(stroke string) (string, bool) {
 	return parts.Left + "AOE", true
 }
 
-func addInitialKHToKPHReplacements(key, value string, originalDictionary *map[string]string,
-	additionalEntries *map[string]string, prefixTree *PrefixTree, ignoredChordPatterns *map[string]bool) {
+func addInitialKHToKPHReplacements(key, value string, ctx *augmentationContext) {
 	newKey, changed := initialKHToKPHReplacementKey(key)
 	if changed {
-		addEntryIfNotPresent(newKey, value, originalDictionary, additionalEntries, prefixTree, ignoredChordPatterns)
+		addEntryIfNotPresent(newKey, value, derivation{rule: ruleInitialKHToKPH, source: key}, ctx)
 	}
 }
 
@@ -666,38 +785,18 @@ func initialKHToKPHReplacementStroke(stroke string) (string, bool) {
 	return leftPrefix + "KPH" + strings.TrimPrefix(leftConsonants, "KH") + parts.Vowels + parts.Right, true
 }
 
-func generateSZVariationForKey(key string, strokes []string, vowelDashRegex *regexp.Regexp, rightHandAfterS *regexp.Regexp,
-	value string, originalDictionary *map[string]string, additionalEntries *map[string]string, prefixTree *PrefixTree, ignoredChordPatterns *map[string]bool) {
-	if strings.HasSuffix(key, "/-S") || strings.HasSuffix(key, "/-Z") {
-		previousStroke := strokes[len(strokes)-2]
-		if vowelDashRegex.MatchString(previousStroke) {
-			previousStroke = getPartAfterVowels(previousStroke)
-		}
-		if strings.HasSuffix(key, "/-S") && !strings.HasSuffix(previousStroke, "S") && !rightHandAfterS.MatchString(previousStroke) {
-			keyVariation1 := strings.TrimSuffix(key, "/-S") + "Z"
-			keyVariation2 := strings.TrimSuffix(key, "/-S") + "S"
-			addEntryIfNotPresent(keyVariation1, value, originalDictionary, additionalEntries, prefixTree, ignoredChordPatterns)
-			addEntryIfNotPresent(keyVariation2, value, originalDictionary, additionalEntries, prefixTree, ignoredChordPatterns)
-		}
-		if strings.HasSuffix(key, "/-Z") && !strings.HasSuffix(previousStroke, "Z") {
-			keyVariation1 := strings.TrimSuffix(key, "/-Z") + "Z"
-			keyVariation2 := strings.TrimSuffix(key, "/-Z") + "S"
-			addEntryIfNotPresent(keyVariation1, value, originalDictionary, additionalEntries, prefixTree, ignoredChordPatterns)
-			addEntryIfNotPresent(keyVariation2, value, originalDictionary, additionalEntries, prefixTree, ignoredChordPatterns)
-		}
-	}
-}
-
-func generateKwrRemovedVariations(key string, strokes []string, originalDictionary *map[string]string) [][]string {
-	// Step 1: Find indexes where strokes[i] starts with "KWR" but is not equal to "KWR"
+// generateGlideRemovedVariations removes the glide (KWR or W) from every subset of the strokes after
+// the first that start with it
+func generateGlideRemovedVariations(key string, strokes []string, originalDictionary *map[string]string, glide string) [][]string {
+	// Step 1: Find indexes where strokes[i] starts with the glide but is not equal to the glide
 	indexes := []int{}
 	for i, stroke := range strokes {
-		if i > 0 && strings.HasPrefix(stroke, "KWR") && stroke != "KWR" {
+		if i > 0 && strings.HasPrefix(stroke, glide) && stroke != glide && (glide != wGlideChord || isWGlider(strokes[i-1], stroke)) {
 			indexes = append(indexes, i)
 		}
 	}
 
-	// Step 2: Generate all combinations of replacing KWR in strokes elements with ""
+	// Step 2: Generate all combinations of replacing the glide in strokes elements with ""
 	replacementOptions := generateReplacementOptions(indexes)
 	var variations [][]string
 	for _, replacement := range replacementOptions {
@@ -706,7 +805,7 @@ func generateKwrRemovedVariations(key string, strokes []string, originalDictiona
 		copy(newStrokes, strokes)
 		for i, shouldReplace := range replacement {
 			if shouldReplace && indexes[i] > 0 {
-				newStrokes[indexes[i]] = strings.TrimPrefix(newStrokes[indexes[i]], "KWR")
+				newStrokes[indexes[i]] = strings.TrimPrefix(newStrokes[indexes[i]], glide)
 			}
 		}
 
@@ -723,6 +822,62 @@ func generateKwrRemovedVariations(key string, strokes []string, originalDictiona
 	return variations
 }
 
+// generateGlideAddedVariations adds the glide (KWR or W) in front of every subset of the vowel initial
+// strokes after the first, most strokes first, keeping at most maxSubsets variations (0 for no limit)
+func generateGlideAddedVariations(key string, strokes []string, originalDictionary *map[string]string, glide string, maxSubsets int) [][]string {
+	indexes := []int{}
+	for i, stroke := range strokes {
+		startsWithVowel := strings.HasPrefix(stroke, "A") || strings.HasPrefix(stroke, "E") ||
+			strings.HasPrefix(stroke, "O") || strings.HasPrefix(stroke, "U")
+		// only replace second stroke or later
+		if i > 0 && startsWithVowel && (glide != wGlideChord || isWGlider(strokes[i-1], wGlideChord+stroke)) {
+			indexes = append(indexes, i)
+		}
+	}
+
+	var variations [][]string
+	// gliding every stroke comes first, as it did before subsets were tried, and subsets stop being
+	// enumerated once there are maxSubsets variations
+	forEachSubsetBySize(len(indexes), func(replacement []bool) bool {
+		newStrokes := make([]string, len(strokes))
+		copy(newStrokes, strokes)
+		for i, shouldReplace := range replacement {
+			if shouldReplace {
+				newStrokes[indexes[i]] = glide + newStrokes[indexes[i]]
+			}
+		}
+		if isDistinctAndValid(key, indexes, replacement, newStrokes, originalDictionary) {
+			variations = append(variations, newStrokes)
+		}
+		return maxSubsets <= 0 || len(variations) < maxSubsets
+	})
+	return variations
+}
+
+// forEachSubsetBySize calls yield with every subset of n items, largest subsets first and in the
+// order of generateReplacementOptions within a size, until yield returns false
+func forEachSubsetBySize(n int, yield func(subset []bool) bool) {
+	subset := func(mask int) []bool {
+		replacement := make([]bool, n)
+		for j := range replacement {
+			replacement[j] = mask&(1<<j) != 0
+		}
+		return replacement
+	}
+	for size := n; size > 0; size-- {
+		// the masks with size bits set in increasing order, see Gosper's hack
+		for mask := 1<<size - 1; mask < 1<<n; {
+			if !yield(subset(mask)) {
+				return
+			}
+			lowest := mask & -mask
+			ripple := mask + lowest
+			mask = ((ripple^mask)>>2)/lowest | ripple
+		}
+	}
+	yield(subset(0))
+}
+
 func generateReplacementOptions(indexes []int) [][]bool {
 	options := [][]bool{}
 	for i := 0; i < (1 << len(indexes)); i++ {
@@ -804,7 +959,7 @@ func countConsonantsAtBeginning(s string) int {
 	return count
 }
 
-func applyOffsetsToStrokes(strokes []string, offsets []int) [][]string {
+func applyOffsetsToStrokes(strokes []string, offsets []int, wGlide bool) [][]string {
 	lhsStenoLetters := []string{
 		"KWR",
 		"PW",
@@ -865,8 +1020,8 @@ func applyOffsetsToStrokes(strokes []string, offsets []int) [][]string {
 
 		// Apply offset
 		if index < len(current)-1 {
-			// Check if the second element starts with KWR followed by a vowel or PW
-			shouldProcess := !isGlider(current[index+1])
+			// Check if the second element starts with KWR followed by a vowel or PW, or with a W glide
+			shouldProcess := !isGlider(current[index+1]) && !(wGlide && isWGlider(current[index], current[index+1]))
 			offset := offsets[index]
 			if shouldProcess && offset < 0 {
 
@@ -959,23 +1114,6 @@ func returnIfContains(list string, char string) string {
 }
 
 func moveRhsPrefixToLhsStroke(lhs, rhsPrefix string) string {
-	alteredRhsLetters := make(map[string]string)
-	//           left hand    right hand
-	alteredRhsLetters["PW"] = "B"      // B
-	alteredRhsLetters["TK"] = "D"      // D
-	alteredRhsLetters["TP"] = "F"      // F
-	alteredRhsLetters["TKPW"] = "G"    // G
-	alteredRhsLetters["SKWR"] = "PBLG" // J
-	alteredRhsLetters["K"] = "BG"      // K
-	alteredRhsLetters["HR"] = "L"      // L
-	alteredRhsLetters["PH"] = "PL"     // M
-	alteredRhsLetters["TPH"] = "PB"    // N
-	alteredRhsLetters["SR"] = "F"      // V
-	alteredRhsLetters["TH"] = "*T"     // TH
-	alteredRhsLetters["KH"] = "FP"
-	alteredRhsLetters["SH"] = "RB"
-	alteredRhsLetters["SR"] = "F"    // V
-	alteredRhsLetters["STKPW"] = "Z" // Z
 	if _, ok := alteredRhsLetters[rhsPrefix]; ok {
 		lookup := alteredRhsLetters[rhsPrefix]
 		if strings.HasPrefix(lookup, "*") {
@@ -994,16 +1132,6 @@ func moveRhsPrefixToLhsStroke(lhs, rhsPrefix string) string {
 }
 
 func moveLhsSuffixToRhsStroke(rhs, lhsPrefix string) string {
-	alteredLhsLetters := make(map[string]string)
-	//           right hand    left hand
-	alteredLhsLetters["PL"] = "PH"     // M
-	alteredLhsLetters["TPH"] = "PB"    // N
-	alteredLhsLetters["F"] = "TP"      // V
-	alteredLhsLetters["BG"] = "K"      // K
-	alteredLhsLetters["BGT"] = "-BGT"  // KT
-	alteredLhsLetters["PBLG"] = "SKWR" // J
-	alteredLhsLetters["FP"] = "CH"
-	alteredLhsLetters["RB"] = "SH"
 	rhsWithoutDash := strings.TrimPrefix(rhs, "-")
 	if _, ok := alteredLhsLetters[lhsPrefix]; ok {
 		lookup := alteredLhsLetters[lhsPrefix]
@@ -1030,12 +1158,26 @@ func isGlider(stroke string) bool {
 	return isVowel(stroke[3])
 }
 
+// wGlideVowels are the vowels that end in a w sound, after which Lapwing can start the next stroke
+// with a W glide, like SOU/WER
+var wGlideVowels = map[string]bool{"O": true, "AO": true, "OE": true, "OU": true, "AOU": true, "U": true}
+
+// isWGlider reports whether stroke starts with a W glide: W followed by a vowel, after a stroke that
+// ends in one of wGlideVowels
+func isWGlider(previous, stroke string) bool {
+	if len(stroke) < 2 || stroke[0] != 'W' || !isVowel(stroke[1]) {
+		return false
+	}
+	parts := strokeParts(previous)
+	return parts.Valid && parts.Right == "" && wGlideVowels[strings.ReplaceAll(parts.Vowels, "*", "")]
+}
+
 func isVowel(r byte) bool {
 	vowels := "AEOU"
 	return strings.ContainsRune(vowels, rune(r))
 }
 
-func generateAlternateSyllableSplitStrokes(strokes []string, originalDictionary *map[string]string, additionalEntries *map[string]string, prefixTree *PrefixTree, ignoredChordPatterns *map[string]bool) [][]string {
+func generateAlternateSyllableSplitStrokes(strokes []string, ctx *augmentationContext) [][]string {
 	var intervals [][]int
 
 	for i := 0; i <= len(strokes)-2; i++ {
@@ -1054,7 +1196,7 @@ func generateAlternateSyllableSplitStrokes(strokes []string, originalDictionary
 	uniqueStrokes[originalStrokes] = true
 
 	for _, combination := range intervalCombinations {
-		appliedStrokes := applyOffsetsToStrokes(strokes, combination)
+		appliedStrokes := applyOffsetsToStrokes(strokes, combination, ctx.wGlide)
 		for _, strokeSet := range appliedStrokes {
 			validStrokes := true
 			for _, stroke := range strokeSet {
@@ -1066,7 +1208,7 @@ func generateAlternateSyllableSplitStrokes(strokes []string, originalDictionary
 			if !validStrokes {
 				continue
 			}
-			validStrokes = validWordBoundaries(strokeSet, originalDictionary, additionalEntries, prefixTree, ignoredChordPatterns)
+			validStrokes = validWordBoundaries(strokeSet, ctx.originalDictionary, ctx.additionalEntries, ctx.prefixTree, ctx.ignoredChordPatterns)
 			if validStrokes {
 				// filter elements of strokeSet that are empty
 				strokeSet = removeEmpty(strokeSet)
@@ -1097,7 +1239,9 @@ func PrefixTreeHasPrefix(prefixTree *PrefixTree, strokes []string) bool {
 	return prefixTree.HasPrefix(strokesCopy)
 }
 
-func validWordBoundaries(strokeSet []string, originalDictionary *map[string]string, additionalEntries *map[string]string, prefixTree *PrefixTree, ignoredChordPatterns *map[string]bool) bool {
+// validWordBoundaries checks that no split of strokeSet reads as two entries, other than the splits
+// before the stroke positions in allowedSplits
+func validWordBoundaries(strokeSet []string, originalDictionary *map[string]string, additionalEntries *map[string]string, prefixTree *PrefixTree, ignoredChordPatterns *map[string]bool, allowedSplits ...int) bool {
 	if len(strokeSet) < 2 {
 		return true
 	}
@@ -1111,6 +1255,9 @@ func validWordBoundaries(strokeSet []string, originalDictionary *map[string]stri
 	// check from right to left
 	for strokesBack := 1; strokesBack < len(strokeSet); strokesBack++ {
 		splitPoint := len(strokeSet) - strokesBack
+		if slices.Contains(allowedSplits, splitPoint) {
+			continue
+		}
 		suffixStrokes := strokeSet[splitPoint:]
 		suffix := strings.Join(suffixStrokes, "/")
 		prefixStrokes := strokeSet[:splitPoint]
@@ -1134,6 +1281,9 @@ func validWordBoundaries(strokeSet []string, originalDictionary *map[string]stri
 	// now check from left to right
 	for strokesForward := 1; strokesForward < len(strokeSet); strokesForward++ {
 		splitPoint := strokesForward
+		if slices.Contains(allowedSplits, splitPoint) {
+			continue
+		}
 		prefixStrokes := strokeSet[:splitPoint]
 		prefix := strings.Join(prefixStrokes, "/")
 		suffixStrokes := strokeSet[splitPoint:]
@@ -1185,18 +1335,20 @@ func removeEmpty(strokeSet []string) []string {
 	return strokeSet
 }
 
-func getPartAfterVowels(input string) string {
-	vowels := "AEOU-*"
-	lastVowelIndex := -1
-	for i := range input {
-		if strings.ContainsRune(vowels, rune(input[i])) {
-			lastVowelIndex = i
-		}
+// readDictionaryInto adds the entries of a Plover JSON dictionary to dict, replacing existing entries
+func readDictionaryInto(path string, dict map[string]string) error {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return err
 	}
-	if lastVowelIndex != -1 {
-		return input[lastVowelIndex+1:]
+	var entries map[string]string
+	if err := json.Unmarshal(contents, &entries); err != nil {
+		return fmt.Errorf("error parsing JSON in %s: %w", path, err)
 	}
-	return input
+	for key, value := range entries {
+		dict[key] = value
+	}
+	return nil
 }
 
 func hasKey(key string, dict *map[string]string) bool {
@@ -1204,21 +1356,74 @@ func hasKey(key string, dict *map[string]string) bool {
 	return ok
 }
 
-func addEntryIfNotPresent(key, value string, originalDict *map[string]string, additionalDict *map[string]string, prefixTree *PrefixTree, ignoredChordPatterns *map[string]bool) bool {
-	if !hasKey(key, originalDict) && !hasKey(key, additionalDict) {
-		strokes := strings.Split(key, "/")
-		if !validWordBoundaries(strokes, originalDict, additionalDict, prefixTree, ignoredChordPatterns) { // check if there is a conflict
+// addEntryIfNotPresent adds key for value if it passes every check, and returns whether an entry was
+// added. With --asterisk_fallback the entry added can be an asterisk variant of key, see
+// augmentationContext.addedOutline.
+func addEntryIfNotPresent(key, value string, origin derivation, ctx *augmentationContext) bool {
+	if existingValue, ok := (*ctx.originalDictionary)[key]; ok {
+		if existingValue == value {
+			return false
+		}
+		ctx.reject(key, value, origin, fmt.Sprintf("outline is already %q in the source dictionaries", existingValue))
+		return addAsteriskFallback(key, value, existingValue, origin, ctx)
+	}
+	if ctx.decisions.rejects(key, value, origin.rule) {
+		return ctx.reject(key, value, origin, rejectedInReview)
+	}
+	if ctx.exclude.matches(key, value) || (origin.source != "" && ctx.exclude.matches(origin.source, value)) {
+		return ctx.reject(key, value, origin, rejectedExcluded)
+	}
+	existingValue, contested := (*ctx.additionalEntries)[key]
+	if contested {
+		if existingValue == value {
 			return false
 		}
+		if ctx.decisions.accepts(key, existingValue) {
+			ctx.reject(key, value, origin, fmt.Sprintf("outline was accepted in review for %q", existingValue))
+			return addAsteriskFallback(key, value, existingValue, origin, ctx)
+		}
+		// a more common word can take over an outline generated for a less common one
+		if ctx.frequencies == nil || ctx.frequencies.rank(value) >= ctx.frequencies.rank(existingValue) {
+			ctx.reject(key, value, origin, fmt.Sprintf("outline was already generated for %q", existingValue))
+			return addAsteriskFallback(key, value, existingValue, origin, ctx)
+		}
+	} else {
+		strokes := strings.Split(key, "/")
+		if !validWordBoundaries(strokes, ctx.originalDictionary, ctx.additionalEntries, ctx.prefixTree, ctx.ignoredChordPatterns, ctx.allowedSplits(key, value)...) { // check if there is a conflict
+			return ctx.reject(key, value, origin, rejectedWordBoundary)
+		}
 		for _, stroke := range strokes {
 			if !isValidStenoOrder(stroke) {
-				return false
+				return ctx.reject(key, value, origin, rejectedStenoOrder)
 			}
 		}
-		(*additionalDict)[key] = value
-		return true
 	}
-	return false
+	// make sure the chords still plausibly spell the word
+	if ctx.minAlignment > 0 && outlineAlignmentScore(key, value) < ctx.minAlignment {
+		return ctx.reject(key, value, origin, rejectedAlignment)
+	}
+	if ctx.maxAlternativesPerWord > 0 && ctx.alternativeCounts[value] >= ctx.maxAlternativesPerWord {
+		return ctx.reject(key, value, origin, rejectedMaxAlternatives)
+	}
+	depth := ctx.derivationDepth(origin)
+	score := candidateScore(key, origin, depth, ctx)
+	if score < ctx.minScore {
+		return ctx.reject(key, value, origin, rejectedMinScore)
+	}
+	// case variants are written differently on purpose, so they aren't compared with their source
+	harder := origin.rule != ruleCaseVariant && ctx.strokeCosts.harder(key, origin.source)
+	if harder && ctx.filterHarderStrokes {
+		return ctx.reject(key, value, origin, rejectedHarder)
+	}
+	if contested {
+		ctx.alternativeCounts[existingValue]--
+		ctx.reject(key, existingValue, ctx.provenance[key].derivation(), fmt.Sprintf("outline was taken over by the more common %q", value))
+	}
+	(*ctx.additionalEntries)[key] = value
+	ctx.alternativeCounts[value]++
+	ctx.provenance[key] = provenanceRecord{Outline: key, Translation: value, Rule: origin.rule, Source: origin.source, Depth: depth, Score: score,
+		StrokeCost: ctx.strokeCosts.outlineCost(key), Harder: harder}
+	return true
 }
 
 type StenoParts struct {
@@ -1,6 +1,10 @@
 package main
 
-import "testing"
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
 
 func TestSuffixReplacementHasBareStem(t *testing.T) {
 	tests := []struct {
@@ -160,3 +164,83 @@ func TestInitialKHToKPHReplacementKey(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateGlideAddedVariations(t *testing.T) {
+	originalDictionary := map[string]string{"KOEPBS": "cons", "KOEPBS/KWREUD": "conside"}
+	tests := []struct {
+		name       string
+		key        string
+		glide      string
+		maxSubsets int
+		want       []string
+	}{
+		{name: "every subset, most glides first", key: "SEUB/OEF/A", glide: kwrGlideChord, maxSubsets: 0,
+			want: []string{"SEUB/KWROEF/KWRA", "SEUB/KWROEF/A", "SEUB/OEF/KWRA"}},
+		{name: "limited subsets", key: "SEUB/OEF/A", glide: kwrGlideChord, maxSubsets: 2,
+			want: []string{"SEUB/KWROEF/KWRA", "SEUB/KWROEF/A"}},
+		{name: "long outline stops at the limit", key: "SEUB" + strings.Repeat("/A", 60), glide: kwrGlideChord, maxSubsets: 1,
+			want: []string{"SEUB" + strings.Repeat("/KWRA", 60)}},
+		{name: "prefix in the dictionary", key: "KOEPBS/EUD/ER", glide: kwrGlideChord, maxSubsets: 0,
+			want: []string{"KOEPBS/EUD/KWRER"}},
+		{name: "no vowel initial strokes", key: "KAT/HRAOG", glide: kwrGlideChord, maxSubsets: 0, want: nil},
+		{name: "w glide after ou", key: "SOU/ER", glide: wGlideChord, maxSubsets: 0, want: []string{"SOU/WER"}},
+		{name: "w glide only after o and u vowels", key: "TKO/EU/ER", glide: wGlideChord, maxSubsets: 0,
+			want: []string{"TKO/WEU/ER"}},
+		{name: "no w glide after a consonant", key: "SOUR/ER", glide: wGlideChord, maxSubsets: 0, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, variation := range generateGlideAddedVariations(tt.key, strings.Split(tt.key, "/"), &originalDictionary, tt.glide, tt.maxSubsets) {
+				got = append(got, strings.Join(variation, "/"))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("generateGlideAddedVariations(%q, %q, %d) = %v, want %v", tt.key, tt.glide, tt.maxSubsets, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateGlideRemovedVariations(t *testing.T) {
+	originalDictionary := map[string]string{"TKO": "do"}
+	tests := []struct {
+		name  string
+		key   string
+		glide string
+		want  []string
+	}{
+		{name: "kwr", key: "SEUB/KWROEF/KWRA", glide: kwrGlideChord,
+			want: []string{"SEUB/KWROEF/A", "SEUB/OEF/A", "SEUB/OEF/KWRA"}},
+		{name: "w glide", key: "SOU/WER", glide: wGlideChord, want: []string{"SOU/ER"}},
+		{name: "w that isn't a glide", key: "SA/WER", glide: wGlideChord, want: nil},
+		{name: "prefix in the dictionary", key: "TKO/WER", glide: wGlideChord, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, variation := range generateGlideRemovedVariations(tt.key, strings.Split(tt.key, "/"), &originalDictionary, tt.glide) {
+				got = append(got, strings.Join(variation, "/"))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("generateGlideRemovedVariations(%q, %q) = %v, want %v", tt.key, tt.glide, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyOffsetsKeepsWGlides(t *testing.T) {
+	// moving the W of SOU/WER to the first stroke would break up the glide
+	for _, wGlide := range []bool{false, true} {
+		moved := false
+		for _, strokes := range applyOffsetsToStrokes([]string{"SOU", "WER"}, []int{1}, wGlide) {
+			if strokes[0] == "SOUW" || strokes[1] == "ER" {
+				moved = true
+			}
+		}
+		if moved == wGlide {
+			t.Fatalf("applyOffsetsToStrokes(SOU/WER, wGlide = %v) moved the W: %v", wGlide, moved)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"strings"
+)
+
+// mergeConflict is an outline that was edited by hand and that the rules now generate differently.
+// An empty translation means the outline has no entry.
+type mergeConflict struct {
+	outline   string
+	snapshot  string
+	edited    string
+	generated string
+}
+
+// snapshotPath is the sidecar file that keeps the last generated version of an output target, so that
+// manual edits can be told apart from changes in the rules
+func snapshotPath(targetPath string) string {
+	return strings.TrimSuffix(targetPath, ".json") + ".snapshot.json"
+}
+
+// mergeManualEdits does a three-way merge of the last generated dictionary, the same dictionary with
+// manual edits and the newly generated dictionary. Outlines that were removed, retranslated or added by
+// hand keep the edit; everything else follows the new rules. Edits to outlines the rules now generate
+// differently are kept as well but reported as conflicts.
+func mergeManualEdits(snapshot, edited, generated *map[string]string) (map[string]string, []mergeConflict) {
+	merged := make(map[string]string)
+	outlines := make(map[string]string)
+	for _, dict := range []*map[string]string{snapshot, edited, generated} {
+		for key := range *dict {
+			outlines[key] = ""
+		}
+	}
+	var conflicts []mergeConflict
+	for _, key := range sortedMapKeys(&outlines) {
+		snapshotValue, inSnapshot := (*snapshot)[key]
+		editedValue, inEdited := (*edited)[key]
+		generatedValue, inGenerated := (*generated)[key]
+
+		if inSnapshot == inEdited && snapshotValue == editedValue {
+			if inGenerated {
+				merged[key] = generatedValue
+			}
+			continue
+		}
+		if inEdited {
+			merged[key] = editedValue
+		}
+		rulesChanged := inSnapshot != inGenerated || snapshotValue != generatedValue
+		if rulesChanged && (inEdited != inGenerated || editedValue != generatedValue) {
+			conflicts = append(conflicts, mergeConflict{outline: key, snapshot: snapshotValue, edited: editedValue, generated: generatedValue})
+		}
+	}
+	return merged, conflicts
+}
+
+// readOptionalDictionary reads a dictionary, returning false if the file doesn't exist
+func readOptionalDictionary(path string) (map[string]string, bool, error) {
+	dict := make(map[string]string)
+	err := readDictionaryInto(path, dict)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	return dict, err == nil, err
+}
+
+func writeDictionary(path string, dict map[string]string) error {
+	contents, err := json.MarshalIndent(dict, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, contents, 0644)
+}
+
+// writeOutputTarget writes the generated entries to a target, keeping manual edits made since the last
+// run unless discardManualEdits is set, and saves the generated entries as the target's snapshot. A
+// target without a snapshot, like one written before snapshots existed, can't be told apart from
+// manual edits, so unless it already holds the generated entries it is left unchanged and seeded as
+// its own snapshot, which the returned bool reports.
+func writeOutputTarget(targetPath string, generated map[string]string, discardManualEdits bool) (map[string]string, []mergeConflict, bool, error) {
+	output := generated
+	var conflicts []mergeConflict
+	if !discardManualEdits {
+		snapshot, hasSnapshot, err := readOptionalDictionary(snapshotPath(targetPath))
+		if err != nil {
+			return nil, nil, false, err
+		}
+		edited, hasEdited, err := readOptionalDictionary(targetPath)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		if hasEdited && !hasSnapshot && !maps.Equal(edited, generated) {
+			return edited, nil, true, writeDictionary(snapshotPath(targetPath), edited)
+		}
+		if hasSnapshot && hasEdited {
+			output, conflicts = mergeManualEdits(&snapshot, &edited, &generated)
+		}
+	}
+	if err := writeDictionary(targetPath, output); err != nil {
+		return nil, nil, false, err
+	}
+	if err := writeDictionary(snapshotPath(targetPath), generated); err != nil {
+		return nil, nil, false, err
+	}
+	return output, conflicts, false, nil
+}
+
+func describeMergedEntry(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return value
+}
+
+func writeSeededSnapshotWarning(out io.Writer, targetPath string) {
+	fmt.Fprintf(out, "%s has no snapshot to tell manual edits apart from generated entries, so it was left unchanged and saved as "+
+		"its snapshot. The next run updates it, keeping the edits you make from now on. Pass --discard_manual_edits to overwrite it now.\n", targetPath)
+}
+
+func writeMergeConflicts(out io.Writer, targetPath string, conflicts []mergeConflict) {
+	if len(conflicts) == 0 {
+		return
+	}
+	fmt.Fprintf(out, "%d manual edits in %s conflict with what the rules now generate, the edits were kept:\n", len(conflicts), targetPath)
+	for _, conflict := range conflicts {
+		fmt.Fprintf(out, "  %s: generated %s, edited to %s, now generated %s\n", conflict.outline,
+			describeMergedEntry(conflict.snapshot), describeMergedEntry(conflict.edited), describeMergedEntry(conflict.generated))
+	}
+}
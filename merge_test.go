@@ -0,0 +1,148 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMergeManualEdits(t *testing.T) {
+	tests := []struct {
+		name              string
+		snapshot          map[string]string
+		edited            map[string]string
+		generated         map[string]string
+		expected          map[string]string
+		expectedConflicts []mergeConflict
+	}{
+		{
+			name:      "no edits follow the rules",
+			snapshot:  map[string]string{"KAT": "cat", "TKOG": "dog"},
+			edited:    map[string]string{"KAT": "cat", "TKOG": "dog"},
+			generated: map[string]string{"KAT": "cat", "PWEUD": "bid"},
+			expected:  map[string]string{"KAT": "cat", "PWEUD": "bid"},
+		},
+		{
+			name:      "removed entries stay removed",
+			snapshot:  map[string]string{"KAT": "cat", "TKOG": "dog"},
+			edited:    map[string]string{"KAT": "cat"},
+			generated: map[string]string{"KAT": "cat", "TKOG": "dog"},
+			expected:  map[string]string{"KAT": "cat"},
+		},
+		{
+			name:      "retranslated and added entries are kept",
+			snapshot:  map[string]string{"KAT": "cat"},
+			edited:    map[string]string{"KAT": "Cat", "TKOG": "dog"},
+			generated: map[string]string{"KAT": "cat"},
+			expected:  map[string]string{"KAT": "Cat", "TKOG": "dog"},
+		},
+		{
+			name:      "edits the rules agree with are not conflicts",
+			snapshot:  map[string]string{"KAT": "cat", "TKOG": "dog"},
+			edited:    map[string]string{"KAT": "Cat"},
+			generated: map[string]string{"KAT": "Cat"},
+			expected:  map[string]string{"KAT": "Cat"},
+		},
+		{
+			name:      "edits to entries the rules changed are kept and reported",
+			snapshot:  map[string]string{"KAT": "cat", "TKOG": "dog", "PWEUD": "bid"},
+			edited:    map[string]string{"KAT": "Cat", "PWEUD": "bid", "HOG": "hog"},
+			generated: map[string]string{"KAT": "cot", "TKOG": "dock", "HOG": "hug"},
+			expected:  map[string]string{"KAT": "Cat", "HOG": "hog"},
+			expectedConflicts: []mergeConflict{
+				{outline: "HOG", snapshot: "", edited: "hog", generated: "hug"},
+				{outline: "KAT", snapshot: "cat", edited: "Cat", generated: "cot"},
+				{outline: "TKOG", snapshot: "dog", edited: "", generated: "dock"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			merged, conflicts := mergeManualEdits(&test.snapshot, &test.edited, &test.generated)
+			if !reflect.DeepEqual(merged, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, merged)
+			}
+			if !reflect.DeepEqual(conflicts, test.expectedConflicts) {
+				t.Errorf("expected conflicts %v, got %v", test.expectedConflicts, conflicts)
+			}
+		})
+	}
+}
+
+func TestWriteOutputTarget(t *testing.T) {
+	targetPath := filepath.Join(t.TempDir(), "lapwing-augmentations.json")
+
+	// the first run has no snapshot to merge against
+	if _, _, _, err := writeOutputTarget(targetPath, map[string]string{"KAT": "cat", "TKOG": "dog"}, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(targetPath, []byte(`{"KAT": "Cat"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	written, conflicts, _, err := writeOutputTarget(targetPath, map[string]string{"KAT": "cat", "TKOG": "dog", "PWEUD": "bid"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{"KAT": "Cat", "PWEUD": "bid"}
+	if !reflect.DeepEqual(written, expected) || len(conflicts) != 0 {
+		t.Errorf("expected %v without conflicts, got %v and %v", expected, written, conflicts)
+	}
+	onDisk := make(map[string]string)
+	if err := readDictionaryInto(targetPath, onDisk); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(onDisk, expected) {
+		t.Errorf("expected %v on disk, got %v", expected, onDisk)
+	}
+	snapshot := make(map[string]string)
+	if err := readDictionaryInto(snapshotPath(targetPath), snapshot); err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshot) != 3 {
+		t.Errorf("expected the snapshot to hold the 3 generated entries, got %v", snapshot)
+	}
+
+	written, _, _, err = writeOutputTarget(targetPath, map[string]string{"KAT": "cat"}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(written, map[string]string{"KAT": "cat"}) {
+		t.Errorf("expected --discard_manual_edits to overwrite the edits, got %v", written)
+	}
+}
+
+func TestWriteOutputTargetWithoutSnapshot(t *testing.T) {
+	targetPath := filepath.Join(t.TempDir(), "lapwing-augmentations.json")
+	if err := os.WriteFile(targetPath, []byte(`{"KAT": "Cat", "TKOG": "dog"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// a target written before snapshots existed may have been edited, so it is kept and seeded
+	written, _, seeded, err := writeOutputTarget(targetPath, map[string]string{"KAT": "cat", "TKOG": "dog", "PWEUD": "bid"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{"KAT": "Cat", "TKOG": "dog"}
+	if !seeded || !reflect.DeepEqual(written, expected) {
+		t.Fatalf("expected the target to be kept and seeded, got %v (seeded %v)", written, seeded)
+	}
+	snapshot := make(map[string]string)
+	if err := readDictionaryInto(snapshotPath(targetPath), snapshot); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(snapshot, expected) {
+		t.Errorf("expected the target to be the snapshot, got %v", snapshot)
+	}
+
+	// the next run merges against the seeded snapshot
+	written, _, seeded, err = writeOutputTarget(targetPath, map[string]string{"KAT": "cat", "TKOG": "dog", "PWEUD": "bid"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seeded || !reflect.DeepEqual(written, map[string]string{"KAT": "cat", "TKOG": "dog", "PWEUD": "bid"}) {
+		t.Errorf("expected the second run to update the target, got %v (seeded %v)", written, seeded)
+	}
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strings"
+)
+
+// undoCorrection is an outline that was undone and the outline written right after it
+type undoCorrection struct {
+	attempt    string
+	correction string
+}
+
+// findUndoCorrections finds the outlines taken back by a run of undo strokes and the outline that was
+// written instead, e.g. TKEU/STREU/PWAOUT, undone and then TKEUS/TREU/PWAOUT. Strokes are split into
+// outlines with strokeTranslator, so each undo takes back a whole outline like Plover's does.
+func findUndoCorrections(events []string, maxOutlineLength int, dicts ...*map[string]string) []undoCorrection {
+	var corrections []undoCorrection
+	translator := newStrokeTranslator(maxOutlineLength, dicts...)
+	for i := 0; i < len(events); {
+		if events[i] != undoStroke {
+			translator.write(events[i])
+			i++
+			continue
+		}
+		var undone []string
+		for ; i < len(events) && events[i] == undoStroke; i++ {
+			undone = slices.Concat(translator.undo(), undone)
+		}
+		attempt := strings.Join(undone, "/")
+
+		// the first outline written after the undos
+		next := newStrokeTranslator(maxOutlineLength, dicts...)
+		for j := i; j < len(events) && events[j] != undoStroke && j-i < maxOutlineLength; j++ {
+			next.write(events[j])
+		}
+		if attempt == "" || len(next.outlines) == 0 {
+			continue
+		}
+		correction := strings.Join(next.outlines[0], "/")
+		if correction == attempt {
+			continue
+		}
+		if hasKeyInAny(correction, dicts...) {
+			corrections = append(corrections, undoCorrection{attempt: attempt, correction: correction})
+		}
+	}
+	return corrections
+}
+
+// misstrokeSuggestion is an outline a writer tried that the rules generated but dropped
+type misstrokeSuggestion struct {
+	Outline     string `json:"outline"`
+	Translation string `json:"translation"`
+	Correction  string `json:"correction"`
+	Count       int    `json:"count"`
+	Rule        string `json:"rule"`
+	Source      string `json:"source"`
+	Reason      string `json:"reason"`
+}
+
+// misstrokeSuggestions looks up why the rules dropped each undone attempt. Attempts that are already
+// entries or that no rule generated aren't suggested.
+func misstrokeSuggestions(corrections []undoCorrection, ctx *augmentationContext) []misstrokeSuggestion {
+	suggestions := make(map[undoCorrection]*misstrokeSuggestion)
+	for _, correction := range corrections {
+		value, ok := (*ctx.originalDictionary)[correction.correction]
+		if !ok {
+			value, ok = (*ctx.additionalEntries)[correction.correction]
+		}
+		if !ok || (*ctx.additionalEntries)[correction.attempt] == value {
+			continue
+		}
+		rejection, ok := ctx.rejectionFor(correction.attempt, value)
+		if !ok {
+			continue
+		}
+		suggestion, ok := suggestions[correction]
+		if !ok {
+			suggestion = &misstrokeSuggestion{
+				Outline:     correction.attempt,
+				Translation: value,
+				Correction:  correction.correction,
+				Rule:        rejection.origin.rule,
+				Source:      rejection.origin.source,
+				Reason:      rejection.reason,
+			}
+			suggestions[correction] = suggestion
+		}
+		suggestion.Count++
+	}
+
+	var result []misstrokeSuggestion
+	for _, suggestion := range suggestions {
+		result = append(result, *suggestion)
+	}
+	slices.SortFunc(result, func(a, b misstrokeSuggestion) int {
+		if a.Count != b.Count {
+			return cmp.Compare(b.Count, a.Count)
+		}
+		return cmp.Compare(a.Outline, b.Outline)
+	})
+	return result
+}
+
+func runMisstrokes(args []string) {
+	logger := log.New(os.Stderr, "LOG: ", log.LstdFlags|log.Lmicroseconds)
+	flags := flag.NewFlagSet("misstrokes", flag.ExitOnError)
+	var (
+		strokeLogPaths  stringList
+		sourceDictPaths stringList
+		targetDictPaths stringList
+	)
+	flags.Var(&strokeLogPaths, "stroke_log", "Plover strokes.log path(s)")
+	flags.Var(&sourceDictPaths, "lapwing_source", "source dictionary path(s)")
+	flags.Var(&targetDictPaths, "output_target", "optional path(s) to write the suggested entries to")
+	augmentationFlags := registerAugmentationFlags(flags)
+	reportPath := flags.String("report", "", "optional path to write the suggestions with the reason each was dropped to")
+	minCount := flags.Int("min_count", 1, "only suggest outlines that were tried at least this many times")
+	flags.Parse(args)
+
+	if len(strokeLogPaths) == 0 || len(sourceDictPaths) == 0 {
+		fmt.Println("Usage: lapwing_augmentor misstrokes --stroke_log <strokes.log> [--stroke_log <strokes.log> ...] --lapwing_source <source-dict> [--lapwing_source <source-dict2> ...] " +
+			"[--report <report>] [--output_target <target-dict> ...]")
+		os.Exit(1)
+	}
+	options, err := augmentationFlags.load(logger)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	options.recordRejections = true
+
+	originalDictionary := make(map[string]string)
+	for _, sourceDictPath := range sourceDictPaths {
+		if err := readDictionaryInto(sourceDictPath, originalDictionary); err != nil {
+			fmt.Println("Error reading source dictionary:", err)
+			os.Exit(1)
+		}
+	}
+	ctx := augmentDictionary(originalDictionary, options, logger)
+
+	maxOutlineLength := longestOutlineLength(ctx.originalDictionary, ctx.additionalEntries)
+	var corrections []undoCorrection
+	for _, strokeLogPath := range strokeLogPaths {
+		file, err := os.Open(strokeLogPath)
+		if err != nil {
+			fmt.Println("Error reading stroke log:", err)
+			os.Exit(1)
+		}
+		events, err := parseStrokeLogEvents(file)
+		file.Close()
+		if err != nil {
+			fmt.Println("Error reading stroke log:", err)
+			os.Exit(1)
+		}
+		corrections = append(corrections, findUndoCorrections(events, maxOutlineLength, ctx.originalDictionary, ctx.additionalEntries)...)
+	}
+
+	suggestions := slices.DeleteFunc(misstrokeSuggestions(corrections, ctx), func(suggestion misstrokeSuggestion) bool {
+		return suggestion.Count < *minCount
+	})
+	fmt.Println("Found", len(corrections), "undone outlines,", len(suggestions), "of them were generated and dropped")
+	suggestedEntries := make(map[string]string)
+	for _, suggestion := range suggestions {
+		fmt.Printf("%s -> %s (%d times, instead of %s): %s rule from %s, %s\n", suggestion.Outline, suggestion.Translation,
+			suggestion.Count, suggestion.Correction, suggestion.Rule, suggestion.Source, suggestion.Reason)
+		suggestedEntries[suggestion.Outline] = suggestion.Translation
+	}
+
+	if *reportPath != "" {
+		contents, err := json.MarshalIndent(suggestions, "", "  ")
+		if err != nil {
+			fmt.Println("Error marshalling JSON:", err)
+			os.Exit(1)
+		}
+		if err := os.WriteFile(*reportPath, contents, 0644); err != nil {
+			fmt.Println("Error writing report:", err)
+			os.Exit(1)
+		}
+	}
+	contents, err := json.MarshalIndent(suggestedEntries, "", "  ")
+	if err != nil {
+		fmt.Println("Error marshalling JSON:", err)
+		os.Exit(1)
+	}
+	for _, targetPath := range targetDictPaths {
+		if err := os.WriteFile(targetPath, contents, 0644); err != nil {
+			fmt.Println("Error writing to target dictionary:", err)
+			os.Exit(1)
+		}
+	}
+}
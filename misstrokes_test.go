@@ -0,0 +1,83 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFindUndoCorrections(t *testing.T) {
+	dict := map[string]string{
+		"TKEUS/TREU/PWAOUT": "distribute",
+		"KAT":               "cat",
+		"TKOG":              "dog",
+	}
+	events := strings.Fields("KAT TKEU STREU PWAOUT * * * TKEUS TREU PWAOUT TKOG * TKOG KAT * *")
+
+	got := findUndoCorrections(events, 3, &dict)
+	want := []undoCorrection{{attempt: "TKEU/STREU/PWAOUT", correction: "TKEUS/TREU/PWAOUT"}}
+	if !slices.Equal(got, want) {
+		t.Fatalf("findUndoCorrections() = %+v, want %+v", got, want)
+	}
+}
+
+func TestFindUndoCorrectionsTakesBackWholeOutlines(t *testing.T) {
+	dict := map[string]string{
+		"TKEUS/TREU/PWAOUT": "distribute",
+		"TKEU/STREU":        "distress",
+	}
+	// TKEU/STREU is one outline, so two undos take back all three strokes
+	events := strings.Fields("TKEU STREU PWAOUT * * TKEUS TREU PWAOUT")
+
+	got := findUndoCorrections(events, 3, &dict)
+	want := []undoCorrection{{attempt: "TKEU/STREU/PWAOUT", correction: "TKEUS/TREU/PWAOUT"}}
+	if !slices.Equal(got, want) {
+		t.Fatalf("findUndoCorrections() = %+v, want %+v", got, want)
+	}
+}
+
+func TestMisstrokeSuggestions(t *testing.T) {
+	ctx := newTestAugmentationContext(map[string]string{"TKEUS/TREU/PWAOUT": "distribute", "KAT": "cat"})
+	ctx.rejections = make(map[string][]rejection)
+	ctx.minScore = 100
+	addEntryIfNotPresent("TKEU/STREU/PWAOUT", "distribute", derivation{rule: ruleAlternateSplit, source: "TKEUS/TREU/PWAOUT"}, ctx)
+
+	corrections := []undoCorrection{
+		{attempt: "TKEU/STREU/PWAOUT", correction: "TKEUS/TREU/PWAOUT"},
+		{attempt: "TKEU/STREU/PWAOUT", correction: "TKEUS/TREU/PWAOUT"},
+		// no rule generated this one
+		{attempt: "KAPT", correction: "KAT"},
+	}
+	got := misstrokeSuggestions(corrections, ctx)
+	want := []misstrokeSuggestion{{
+		Outline:     "TKEU/STREU/PWAOUT",
+		Translation: "distribute",
+		Correction:  "TKEUS/TREU/PWAOUT",
+		Count:       2,
+		Rule:        ruleAlternateSplit,
+		Source:      "TKEUS/TREU/PWAOUT",
+		Reason:      rejectedMinScore,
+	}}
+	if !slices.Equal(got, want) {
+		t.Fatalf("misstrokeSuggestions() = %+v, want %+v", got, want)
+	}
+}
+
+func TestAddEntryIfNotPresentRecordsRejections(t *testing.T) {
+	ctx := newTestAugmentationContext(map[string]string{"KAT": "cat"})
+	ctx.rejections = make(map[string][]rejection)
+
+	addEntryIfNotPresent("KAT", "kat", derivation{rule: ruleLongO, source: "KOT"}, ctx)
+	addEntryIfNotPresent("KTA", "kat", derivation{rule: ruleStringReplacement, source: "KAT"}, ctx)
+	addEntryIfNotPresent("KAT", "cat", derivation{rule: ruleSZFold, source: "KAT/-S"}, ctx)
+
+	if rejection, ok := ctx.rejectionFor("KAT", "kat"); !ok || rejection.reason != `outline is already "cat" in the source dictionaries` {
+		t.Fatalf("unexpected rejection for KAT: %+v", rejection)
+	}
+	if rejection, ok := ctx.rejectionFor("KTA", "kat"); !ok || rejection.reason != rejectedStenoOrder || rejection.origin.rule != ruleStringReplacement {
+		t.Fatalf("unexpected rejection for KTA: %+v", rejection)
+	}
+	if _, ok := ctx.rejectionFor("KAT", "cat"); ok {
+		t.Fatal("an outline that is already the entry isn't a rejection")
+	}
+}
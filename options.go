@@ -0,0 +1,156 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math"
+)
+
+// augmentationOptions are the settings of a run of augmentDictionary
+type augmentationOptions struct {
+	casePolicy             caseVariantPolicy
+	pronunciations         *PronunciationDictionary
+	hyphenation            *HyphenationPatterns
+	frequencies            *WordFrequencies
+	strokeCosts            *StrokeCostModel
+	minAlignment           float64
+	stressAwareVowels      bool
+	maxAlternativesPerWord int
+	minScore               float64
+	filterHarderStrokes    bool
+	fillInflectionFamilies bool
+	maxKwrInsertionSubsets int
+	wGlide                 bool
+	maxWInsertionSubsets   int
+	asteriskFallback       bool
+	decisions              *ReviewDecisions
+	include                *entryFilter
+	exclude                *entryFilter
+	// keep the reason every rejected candidate was dropped, see augmentationContext.rejections
+	recordRejections bool
+}
+
+// augmentationFlags are the command line flags that control how entries are generated, shared by
+// the main command and the subcommands that rerun the generation
+type augmentationFlags struct {
+	casePolicy             *string
+	generatedCaseVariants  *bool
+	pronunciationsPath     *string
+	minAlignment           *float64
+	stressAwareVowels      *bool
+	frequencyPath          *string
+	maxAlternativesPerWord *int
+	hyphenationPath        *string
+	minScore               *float64
+	strokeCostModelPath    *string
+	filterHarderStrokes    *bool
+	fillInflectionFamilies *bool
+	maxKwrInsertionSubsets *int
+	wGlide                 *bool
+	maxWInsertionSubsets   *int
+	asteriskFallback       *bool
+	decisionsPath          *string
+	includePaths           stringList
+	excludePaths           stringList
+}
+
+func registerAugmentationFlags(flags *flag.FlagSet) *augmentationFlags {
+	f := &augmentationFlags{
+		casePolicy: flags.String("case_variants", caseVariantsFirstWord, "proper name variants: "+
+			"first (capitalize the first word), title (capitalize every word) or off"),
+		generatedCaseVariants: flags.Bool("generated_case_variants", true, "also add case variants for generated entries"),
+		pronunciationsPath:    flags.String("pronunciations", "", "optional CMU Pronouncing Dictionary style file used to check and rank alternate splits"),
+		minAlignment:          flags.Float64("min_alignment", 0, "minimum score (0 to 1) for how well the chords of a generated outline spell its translation, 0 to disable"),
+		stressAwareVowels: flags.Bool("stress_aware_vowels", false, "only offer vowel alternatives like EU -> AOE in syllables that are unstressed "+
+			"or whose vowel matches, using --pronunciations"),
+		frequencyPath: flags.String("frequency", "", "optional word list ordered from most to least common, or with a count after each word, "+
+			"used to decide which word keeps a contested outline"),
+		maxAlternativesPerWord: flags.Int("max_alternatives_per_word", 0, "maximum number of generated outlines per translation, 0 for no limit"),
+		hyphenationPath: flags.String("hyphenation_patterns", "", "optional TeX hyphenation pattern file used to check and rank alternate splits "+
+			"of words without pronunciations"),
+		minScore:            flags.Float64("min_score", math.Inf(-1), "minimum quality score for a generated outline, see the README for how it is calculated"),
+		strokeCostModelPath: flags.String("stroke_cost_model", "", "optional JSON file with the finger assignment and costs used to judge how hard outlines are to write"),
+		filterHarderStrokes: flags.Bool("filter_harder_strokes", false, "drop generated outlines that are harder to write than the outline they were generated from"),
+		fillInflectionFamilies: flags.Bool("fill_inflection_families", false, "also add base forms for generated inflected outlines, like walk for a generated walked, "+
+			"so the rules apply to whole inflection families"),
+		maxKwrInsertionSubsets: flags.Int("max_kwr_insertion_subsets", 16, "maximum number of ways to add KWR to the vowel initial strokes of a generated outline, 0 for no limit"),
+		wGlide:                 flags.Bool("w_glide", false, "also remove and add W glides after O and U vowels (SOU/WER) the way KWR glides are"),
+		maxWInsertionSubsets:   flags.Int("max_w_insertion_subsets", 16, "maximum number of ways to add W to the vowel initial strokes of a generated outline with --w_glide, 0 for no limit"),
+		asteriskFallback:       flags.Bool("asterisk_fallback", false, "when an outline already belongs to another word, add a free asterisk variant of it instead"),
+		decisionsPath:          flags.String("decisions", "", "optional decisions file written by the review subcommand"),
+	}
+	flags.Var(&f.includePaths, "include", "optional file(s) of outline:, word:, glob: and regex: lines, only matching source entries are augmented")
+	flags.Var(&f.excludePaths, "exclude", "optional file(s) of outline:, word:, glob: and regex: lines, matching entries are never augmented or generated")
+	return f
+}
+
+// load reads the files named by the flags
+func (f *augmentationFlags) load(logger *log.Logger) (augmentationOptions, error) {
+	options := augmentationOptions{
+		minAlignment:           *f.minAlignment,
+		stressAwareVowels:      *f.stressAwareVowels,
+		maxAlternativesPerWord: *f.maxAlternativesPerWord,
+		minScore:               *f.minScore,
+		filterHarderStrokes:    *f.filterHarderStrokes,
+		fillInflectionFamilies: *f.fillInflectionFamilies,
+		maxKwrInsertionSubsets: *f.maxKwrInsertionSubsets,
+		wGlide:                 *f.wGlide,
+		maxWInsertionSubsets:   *f.maxWInsertionSubsets,
+		asteriskFallback:       *f.asteriskFallback,
+		strokeCosts:            DefaultStrokeCostModel(),
+	}
+	caseVariantMode, err := parseCaseVariantMode(*f.casePolicy)
+	if err != nil {
+		return options, err
+	}
+	options.casePolicy = caseVariantPolicy{mode: caseVariantMode, generated: *f.generatedCaseVariants}
+
+	if *f.pronunciationsPath != "" {
+		logger.Println("Reading in pronunciations from ", *f.pronunciationsPath)
+		options.pronunciations, err = LoadPronunciationDictionary(*f.pronunciationsPath)
+		if err != nil {
+			return options, fmt.Errorf("reading pronunciations: %w", err)
+		}
+	}
+	if options.stressAwareVowels && options.pronunciations == nil {
+		return options, fmt.Errorf("--stress_aware_vowels needs a --pronunciations file")
+	}
+	if *f.hyphenationPath != "" {
+		logger.Println("Reading in hyphenation patterns from ", *f.hyphenationPath)
+		options.hyphenation, err = LoadHyphenationPatterns(*f.hyphenationPath)
+		if err != nil {
+			return options, fmt.Errorf("reading hyphenation patterns: %w", err)
+		}
+	}
+	if *f.frequencyPath != "" {
+		logger.Println("Reading in word frequencies from ", *f.frequencyPath)
+		options.frequencies, err = LoadWordFrequencies(*f.frequencyPath)
+		if err != nil {
+			return options, fmt.Errorf("reading word frequencies: %w", err)
+		}
+	}
+	if *f.strokeCostModelPath != "" {
+		logger.Println("Reading in stroke cost model from ", *f.strokeCostModelPath)
+		options.strokeCosts, err = LoadStrokeCostModel(*f.strokeCostModelPath)
+		if err != nil {
+			return options, fmt.Errorf("reading stroke cost model: %w", err)
+		}
+	}
+	if *f.decisionsPath != "" {
+		logger.Println("Reading in review decisions from ", *f.decisionsPath)
+		options.decisions, err = LoadReviewDecisions(*f.decisionsPath)
+		if err != nil {
+			return options, fmt.Errorf("reading review decisions: %w", err)
+		}
+	}
+	options.include, err = LoadEntryFilter(f.includePaths)
+	if err != nil {
+		return options, fmt.Errorf("reading --include files: %w", err)
+	}
+	options.exclude, err = LoadEntryFilter(f.excludePaths)
+	if err != nil {
+		return options, fmt.Errorf("reading --exclude files: %w", err)
+	}
+	return options, nil
+}
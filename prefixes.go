@@ -0,0 +1,100 @@
+package main
+
+import "strings"
+
+// prefixInventory is built from the {...^} entries of the source dictionaries
+type prefixInventory struct {
+	// prefix stroke -> prefix, like RE -> {re^}
+	prefixes map[string]string
+	// prefix -> its strokes
+	strokes map[string][]string
+}
+
+func isPrefixTranslation(value string) bool {
+	return len(value) > len("{^}") && strings.HasPrefix(value, "{") && strings.HasSuffix(value, "^}") && !strings.HasPrefix(value, "{^")
+}
+
+// newPrefixInventory collects the single stroke prefix entries
+func newPrefixInventory(dict *map[string]string) *prefixInventory {
+	inventory := &prefixInventory{prefixes: make(map[string]string), strokes: make(map[string][]string)}
+	for _, key := range sortedMapKeys(dict) {
+		value := (*dict)[key]
+		if strings.Contains(key, "/") || !isPrefixTranslation(value) || !strokeParts(key).Valid {
+			continue
+		}
+		inventory.prefixes[key] = value
+		inventory.strokes[value] = append(inventory.strokes[value], key)
+	}
+	return inventory
+}
+
+// mergeStrokes combines two strokes into one when every bank the first stroke uses comes no later than
+// every bank the second one uses, like S + TKRAOEUF or RE + -FT
+func mergeStrokes(first, second StenoParts) (StenoParts, bool) {
+	_, firstLast := bankRange(first)
+	secondFirst, _ := bankRange(second)
+	if firstLast == -1 || secondFirst == -1 || firstLast > secondFirst {
+		return StenoParts{}, false
+	}
+	return StenoParts{
+		Left:   first.Left + second.Left,
+		Vowels: first.Vowels + second.Vowels,
+		Right:  first.Right + second.Right,
+		Valid:  true,
+	}, true
+}
+
+// bankRange returns the first and last bank (0 left, 1 vowels, 2 right) a stroke uses, or -1 if it is empty
+func bankRange(parts StenoParts) (int, int) {
+	first, last := -1, -1
+	for i, bank := range []string{parts.Left, parts.Vowels, parts.Right} {
+		if bank == "" {
+			continue
+		}
+		if first == -1 {
+			first = i
+		}
+		last = i
+	}
+	return first, last
+}
+
+// prefixFolds returns the outlines made by folding the prefix stroke an outline starts with, or just
+// the prefix stroke's left bank, into the word's first stroke, like TKEUS/HRAOEUBG -> TKHRAOEUBG.
+// Every stroke the dictionaries have for the prefix is tried.
+func (inventory *prefixInventory) prefixFolds(strokes []string) [][]string {
+	if len(strokes) < 2 {
+		return nil
+	}
+	prefix, ok := inventory.prefixes[strokes[0]]
+	if !ok {
+		return nil
+	}
+	word := strokeParts(strokes[1])
+	if !word.Valid {
+		return nil
+	}
+	var folds [][]string
+	seen := make(map[string]bool)
+	for _, prefixStroke := range inventory.strokes[prefix] {
+		parts := strokeParts(prefixStroke)
+		// the whole prefix stroke, or just its left bank like TK for TKEUS
+		for _, folded := range []StenoParts{parts, {Left: parts.Left}} {
+			merged, ok := mergeStrokes(folded, word)
+			if !ok {
+				continue
+			}
+			if stroke, ok := validMergedStroke(merged); ok && !seen[stroke] {
+				seen[stroke] = true
+				folds = append(folds, replaceStrokePair(strokes, 0, stroke))
+			}
+		}
+	}
+	return folds
+}
+
+func addPrefixFolds(key, value string, inventory *prefixInventory, ctx *augmentationContext) {
+	for _, folded := range inventory.prefixFolds(strings.Split(key, "/")) {
+		addEntryIfNotPresent(strings.Join(folded, "/"), value, derivation{rule: rulePrefixFold, source: key}, ctx)
+	}
+}
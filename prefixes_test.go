@@ -0,0 +1,77 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewPrefixInventory(t *testing.T) {
+	inventory := newPrefixInventory(&map[string]string{
+		"RE":    "{re^}",
+		"RAOE":  "{re^}",
+		"TKEUS": "{dis^}",
+		"-G":    "{^ing}",
+		"STKPW": "{^}",
+		"UPB/-": "{un^}",
+		"KAT":   "cat",
+	})
+
+	expectedPrefixes := map[string]string{"RE": "{re^}", "RAOE": "{re^}", "TKEUS": "{dis^}"}
+	if !reflect.DeepEqual(inventory.prefixes, expectedPrefixes) {
+		t.Errorf("expected prefix strokes %v, got %v", expectedPrefixes, inventory.prefixes)
+	}
+	expectedStrokes := map[string][]string{"{re^}": {"RE", "RAOE"}, "{dis^}": {"TKEUS"}}
+	if !reflect.DeepEqual(inventory.strokes, expectedStrokes) {
+		t.Errorf("expected strokes %v, got %v", expectedStrokes, inventory.strokes)
+	}
+}
+
+func TestPrefixFolds(t *testing.T) {
+	inventory := newPrefixInventory(&map[string]string{
+		"TKEUS": "{dis^}",
+		"RE":    "{re^}",
+		"S":     "{dis^}",
+		"UPB":   "{un^}",
+	})
+
+	tests := []struct {
+		name     string
+		strokes  []string
+		expected [][]string
+	}{
+		{
+			name:     "left bank of the prefix",
+			strokes:  []string{"TKEUS", "HRAOEUBG"},
+			expected: [][]string{{"SHRAOEUBG"}, {"TKHRAOEUBG"}},
+		},
+		{
+			name:     "whole prefix stroke",
+			strokes:  []string{"RE", "-FT", "-D"},
+			expected: [][]string{{"REFT", "-D"}, {"RFT", "-D"}},
+		},
+		{
+			name:     "prefix out of steno order",
+			strokes:  []string{"RE", "TKO"},
+			expected: nil,
+		},
+		{
+			name:     "prefix without a left bank",
+			strokes:  []string{"UPB", "TKO"},
+			expected: nil,
+		},
+		{
+			name:     "prefix stroke not at the start",
+			strokes:  []string{"KAT", "TKEUS", "HRAOEUBG"},
+			expected: nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			folds := inventory.prefixFolds(test.strokes)
+			if !reflect.DeepEqual(folds, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, folds)
+			}
+		})
+	}
+}
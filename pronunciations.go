@@ -0,0 +1,293 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// PronunciationDictionary holds CMU Pronouncing Dictionary style pronunciations keyed by lowercased word
+type PronunciationDictionary struct {
+	pronunciations map[string][][]string
+}
+
+type syllable struct {
+	onset   []string
+	nucleus string
+	stress  int
+	coda    []string
+}
+
+// onsets that can start an English syllable besides any single consonant other than NG
+var legalOnsetClusters = map[string]bool{
+	"P R": true, "P L": true, "P Y": true,
+	"B R": true, "B L": true, "B Y": true,
+	"T R": true, "T W": true,
+	"D R": true, "D W": true,
+	"K R": true, "K L": true, "K W": true, "K Y": true,
+	"G R": true, "G L": true, "G W": true,
+	"F R": true, "F L": true, "F Y": true,
+	"V Y": true, "M Y": true, "HH Y": true,
+	"TH R": true, "TH W": true, "SH R": true,
+	"S P": true, "S T": true, "S K": true, "S M": true, "S N": true, "S L": true, "S W": true, "S F": true,
+	"S P R": true, "S P L": true, "S P Y": true,
+	"S T R": true,
+	"S K R": true, "S K W": true, "S K L": true, "S K Y": true,
+}
+
+func LoadPronunciationDictionary(path string) (*PronunciationDictionary, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	dictionary := &PronunciationDictionary{pronunciations: make(map[string][][]string)}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, ";;;") {
+			continue
+		}
+		// some versions of the dictionary have trailing "# comments"
+		if commentIndex := strings.Index(line, " #"); commentIndex != -1 {
+			line = line[:commentIndex]
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		// alternate pronunciations are written as WORD(1), WORD(2), ...
+		word := strings.ToLower(fields[0])
+		if parenIndex := strings.Index(word, "("); parenIndex > 0 {
+			word = word[:parenIndex]
+		}
+		phonemes := make([]string, 0, len(fields)-1)
+		for _, phoneme := range fields[1:] {
+			phonemes = append(phonemes, strings.ToUpper(phoneme))
+		}
+		dictionary.pronunciations[word] = append(dictionary.pronunciations[word], phonemes)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return dictionary, nil
+}
+
+// lookup returns the pronunciations of a single word translation, ignoring case
+func (d *PronunciationDictionary) lookup(value string) [][]string {
+	if d == nil {
+		return nil
+	}
+	word := strings.ToLower(strings.TrimSpace(value))
+	if word == "" || strings.ContainsFunc(word, func(r rune) bool { return !unicode.IsLetter(r) && r != '\'' }) {
+		return nil
+	}
+	return d.pronunciations[word]
+}
+
+func isVowelPhoneme(phoneme string) bool {
+	return phoneme != "" && phoneme[len(phoneme)-1] >= '0' && phoneme[len(phoneme)-1] <= '2'
+}
+
+func isLegalOnset(consonants []string) bool {
+	switch len(consonants) {
+	case 0:
+		return true
+	case 1:
+		return consonants[0] != "NG"
+	}
+	return legalOnsetClusters[strings.Join(consonants, " ")]
+}
+
+// rough sonority scale used to decide which consonant clusters can end a syllable
+var consonantSonority = map[string]int{
+	"P": 1, "B": 1, "T": 1, "D": 1, "K": 1, "G": 1,
+	"CH": 2, "JH": 2,
+	"F": 3, "V": 3, "TH": 3, "DH": 3, "S": 3, "Z": 3, "SH": 3, "ZH": 3, "HH": 3,
+	"M": 4, "N": 4, "NG": 4,
+	"L": 5, "R": 5,
+	"W": 6, "Y": 6,
+}
+
+// isLegalCoda allows codas whose sonority doesn't rise, plus trailing inflections like the "s" in "lapse"
+func isLegalCoda(consonants []string) bool {
+	for i, consonant := range consonants {
+		if consonant == "HH" || consonant == "W" || consonant == "Y" {
+			return false
+		}
+		if i == 0 {
+			continue
+		}
+		isTrailingInflection := i == len(consonants)-1 && (consonant == "S" || consonant == "Z" || consonant == "T" || consonant == "D")
+		if consonantSonority[consonant] > consonantSonority[consonants[i-1]] && !isTrailingInflection {
+			return false
+		}
+	}
+	return true
+}
+
+// legalBoundaryLengths lists how many of the consonants between two vowels can start the next syllable
+// while leaving a legal end for the previous one
+func legalBoundaryLengths(consonants []string) []int {
+	var lengths, onsetLengths []int
+	for length := 0; length <= len(consonants); length++ {
+		if !isLegalOnset(consonants[len(consonants)-length:]) {
+			continue
+		}
+		onsetLengths = append(onsetLengths, length)
+		if isLegalCoda(consonants[:len(consonants)-length]) {
+			lengths = append(lengths, length)
+		}
+	}
+	if len(lengths) == 0 {
+		// clusters from loan words may not fit either rule, so only require a legal onset
+		return onsetLengths
+	}
+	return lengths
+}
+
+// syllabify splits phonemes into syllables using the maximal onset principle
+func syllabify(phonemes []string) []syllable {
+	var syllables []syllable
+	var consonants []string
+	for _, phoneme := range phonemes {
+		if !isVowelPhoneme(phoneme) {
+			consonants = append(consonants, phoneme)
+			continue
+		}
+		onset := consonants
+		if len(syllables) > 0 {
+			lengths := legalBoundaryLengths(consonants)
+			onsetLength := lengths[len(lengths)-1]
+			previous := &syllables[len(syllables)-1]
+			previous.coda = consonants[:len(consonants)-onsetLength]
+			onset = consonants[len(consonants)-onsetLength:]
+		}
+		syllables = append(syllables, syllable{
+			onset:   onset,
+			nucleus: phoneme[:len(phoneme)-1],
+			stress:  int(phoneme[len(phoneme)-1] - '0'),
+		})
+		consonants = nil
+	}
+	if len(syllables) > 0 {
+		syllables[len(syllables)-1].coda = consonants
+	}
+	return syllables
+}
+
+// splitPronunciationScore checks every stroke boundary of strokes against the syllable boundaries of
+// a pronunciation. It returns false if the stroke count doesn't match the syllable count or if a
+// stroke starts with a consonant cluster that can't begin a syllable there. The score is higher
+// the closer each stroke boundary is to the maximal onset split.
+func splitPronunciationScore(strokes []string, phonemes []string) (int, bool) {
+	syllables := syllabify(phonemes)
+	if len(syllables) != len(strokes) {
+		return 0, false
+	}
+	score := 0
+	for i := 1; i < len(syllables); i++ {
+		consonants := append(slices.Clone(syllables[i-1].coda), syllables[i].onset...)
+		lengths := legalBoundaryLengths(consonants)
+		strokeOnset := leftBankSoundCount(strokes[i])
+		if isGlider(strokes[i]) && !slices.Equal(syllables[i].onset, []string{"Y"}) {
+			// KWR links vowels that have no consonant between them
+			strokeOnset = 0
+		}
+		if !slices.Contains(lengths, strokeOnset) {
+			return 0, false
+		}
+		score -= abs(lengths[len(lengths)-1] - strokeOnset)
+	}
+	return score, true
+}
+
+func (d *PronunciationDictionary) knowsWord(value string) bool {
+	return len(d.lookup(value)) > 0
+}
+
+// judgeSplit compares strokes with every pronunciation of value and keeps the best score. Splits that
+// can't be compared because no pronunciation has the same number of syllables are unknown.
+func (d *PronunciationDictionary) judgeSplit(value string, strokes []string) splitJudgement {
+	var judgement splitJudgement
+	comparable := false
+	for _, phonemes := range d.lookup(value) {
+		if len(syllabify(phonemes)) == len(strokes) {
+			comparable = true
+		}
+		score, ok := splitPronunciationScore(strokes, phonemes)
+		if ok && (!judgement.known || score > judgement.score) {
+			judgement.score = score
+			judgement.known = true
+		}
+	}
+	judgement.rejected = comparable && !judgement.known
+	return judgement
+}
+
+// phonemes each vowel bank stands for, used to decide whether a vowel alternative fits a stressed syllable
+var vowelPhonemes = map[string][]string{
+	"A":    {"AE", "AH", "AA"},
+	"O":    {"AA", "AO", "AH"},
+	"E":    {"EH", "AH", "IH"},
+	"U":    {"AH", "UH"},
+	"AO":   {"UW", "UH"},
+	"AE":   {"IY", "EY", "EH", "AE"},
+	"AU":   {"AO", "AA"},
+	"EU":   {"IH", "IY", "AH"},
+	"OE":   {"OW"},
+	"OU":   {"AW"},
+	"AOE":  {"IY"},
+	"AOU":  {"UW"},
+	"AEU":  {"EY"},
+	"OEU":  {"OY"},
+	"AOEU": {"AY"},
+}
+
+// vowel banks produced by the EU -> AOE/AE alternatives and the /A -> /A* rules
+var stressCheckedVowels = map[string]bool{
+	"AOE": true,
+	"AE":  true,
+	"A*":  true,
+}
+
+// allowsVowelAlternative checks the strokes whose vowels changed from oldKey to newKey against the
+// pronunciations of value. Strokes are lined up with syllables from the end of the word, so -y endings
+// written with AOE are allowed while AOE for a stressed short i is not. The alternative is allowed
+// if the syllable is unstressed or its vowel is one the new vowel bank stands for.
+// Words without a pronunciation are always allowed.
+func (d *PronunciationDictionary) allowsVowelAlternative(value, oldKey, newKey string) bool {
+	pronunciations := d.lookup(value)
+	if len(pronunciations) == 0 {
+		return true
+	}
+	oldStrokes := strings.Split(oldKey, "/")
+	newStrokes := strings.Split(newKey, "/")
+	for fromEnd := 1; fromEnd <= min(len(oldStrokes), len(newStrokes)); fromEnd++ {
+		oldVowels := separateStrokeParts(oldStrokes[len(oldStrokes)-fromEnd]).Vowels
+		newVowels := separateStrokeParts(newStrokes[len(newStrokes)-fromEnd]).Vowels
+		if oldVowels == newVowels || !stressCheckedVowels[newVowels] {
+			continue
+		}
+		allowed := false
+		for _, phonemes := range pronunciations {
+			syllables := syllabify(phonemes)
+			if fromEnd > len(syllables) {
+				allowed = true
+				break
+			}
+			syllable := syllables[len(syllables)-fromEnd]
+			if syllable.stress == 0 || slices.Contains(vowelPhonemes[strings.ReplaceAll(newVowels, "*", "")], syllable.nucleus) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSyllabify(t *testing.T) {
+	tests := []struct {
+		name     string
+		phonemes string
+		want     []string
+	}{
+		{name: "maximal onset", phonemes: "D IH0 S T R IH1 B Y UW0 T", want: []string{"D IH", "S T R IH", "B Y UW T"}},
+		{name: "single consonant", phonemes: "HH AE1 P IY0", want: []string{"HH AE", "P IY"}},
+		{name: "illegal onset cluster", phonemes: "K AH0 N S IH1 D ER0", want: []string{"K AH N", "S IH", "D ER"}},
+		{name: "ng never starts a syllable", phonemes: "S IH1 NG ER0", want: []string{"S IH NG", "ER"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, syllable := range syllabify(strings.Fields(tt.phonemes)) {
+				parts := append(slices.Clone(syllable.onset), syllable.nucleus)
+				got = append(got, strings.Join(append(parts, syllable.coda...), " "))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("syllabify(%q) = %q, want %q", tt.phonemes, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitPronunciationScore(t *testing.T) {
+	distribute := strings.Fields("D IH0 S T R IH1 B Y UW0 T")
+	tests := []struct {
+		name      string
+		strokes   string
+		phonemes  []string
+		wantScore int
+		wantOk    bool
+	}{
+		{name: "maximal onset split", strokes: "TKEU/STREU/PWAOUT", phonemes: distribute, wantScore: -1, wantOk: true},
+		{name: "lapwing split", strokes: "TKEUS/TREU/PWAOUT", phonemes: distribute, wantScore: -2, wantOk: true},
+		{name: "illegal coda", strokes: "TKEUSTR/EU/PWAOUT", phonemes: distribute, wantOk: false},
+		{name: "consonant moved away from glide", strokes: "TKEU/STREUB/AOUT", phonemes: distribute, wantOk: false},
+		{name: "kwr linker", strokes: "HAP/KWREU", phonemes: strings.Fields("HH AE1 P IY0"), wantScore: -1, wantOk: true},
+		{name: "syllable count mismatch", strokes: "TKEUS/TREUB", phonemes: distribute, wantOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotScore, gotOk := splitPronunciationScore(strings.Split(tt.strokes, "/"), tt.phonemes)
+			if gotOk != tt.wantOk || (gotOk && gotScore != tt.wantScore) {
+				t.Fatalf("splitPronunciationScore(%q) = (%d, %v), want (%d, %v)", tt.strokes, gotScore, gotOk, tt.wantScore, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestAllowsVowelAlternative(t *testing.T) {
+	pronunciations := &PronunciationDictionary{pronunciations: map[string][][]string{
+		"happy":      {strings.Fields("HH AE1 P IY0")},
+		"kit":        {strings.Fields("K IH1 T")},
+		"distribute": {strings.Fields("D IH0 S T R IH1 B Y UW0 T")},
+		"sofa":       {strings.Fields("S OW1 F AH0")},
+	}}
+	tests := []struct {
+		name   string
+		value  string
+		oldKey string
+		newKey string
+		want   bool
+	}{
+		{name: "unstressed y ending", value: "happy", oldKey: "HAP/KWREU", newKey: "HAP/KWRAOE", want: true},
+		{name: "stressed short i", value: "kit", oldKey: "KEUT", newKey: "KAOET", want: false},
+		{name: "unstressed first syllable", value: "distribute", oldKey: "TKEUS/TREU/PWAOUT", newKey: "TKAOES/TREU/PWAOUT", want: true},
+		{name: "stressed second syllable", value: "distribute", oldKey: "TKEUS/TREU/PWAOUT", newKey: "TKEUS/TRAOE/PWAOUT", want: false},
+		{name: "folded strokes lined up from the end", value: "happy", oldKey: "HA/-P/KWREU", newKey: "HA/PAE", want: true},
+		{name: "unstressed a with asterisk", value: "sofa", oldKey: "SOE/TPA", newKey: "SOE/TPA*", want: true},
+		{name: "other vowel changes are not checked", value: "kit", oldKey: "KEUT", newKey: "KOET", want: true},
+		{name: "unknown word", value: "zzyzx", oldKey: "STKPWEU", newKey: "STKPWAOE", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pronunciations.allowsVowelAlternative(tt.value, tt.oldKey, tt.newKey)
+			if got != tt.want {
+				t.Fatalf("allowsVowelAlternative(%q, %q, %q) = %v, want %v", tt.value, tt.oldKey, tt.newKey, got, tt.want)
+			}
+		})
+	}
+}
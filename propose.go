@@ -0,0 +1,283 @@
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strings"
+)
+
+const (
+	// words with more syllables than this get no proposals
+	maxProposalSyllables = 6
+	// only the best this many outlines for a word are kept while building them
+	maxProposalCandidates = 500
+)
+
+// phonemeLeftChord is the left hand chord for a consonant phoneme
+func phonemeLeftChord(phoneme string) (string, bool) {
+	chord, ok := phonemeChords(phoneme)
+	return chord.left, ok && chord.left != ""
+}
+
+// phonemeRightChord is the right hand chord for a consonant phoneme, with * for TH
+func phonemeRightChord(phoneme string) (string, bool) {
+	chord, ok := phonemeChords(phoneme)
+	return chord.right, ok && chord.right != ""
+}
+
+// vowelChordOptions are the vowel banks that can write a vowel phoneme, from vowelPhonemes. The ER
+// phoneme is written as a vowel followed by -R.
+func vowelChordOptions(nucleus string) (options []string, rightPrefix string) {
+	if nucleus == "ER" {
+		return []string{"E", "U"}, "R"
+	}
+	for _, vowels := range sortedMapKeys(&vowelPhonemes) {
+		if slices.Contains(vowelPhonemes[vowels], nucleus) {
+			options = append(options, vowels)
+		}
+	}
+	return options, ""
+}
+
+// proposedStroke writes a syllable as a stroke. Syllables after the first without an onset start
+// with the KWR glide, like Lapwing does.
+func proposedStroke(onset []string, vowels string, coda []string, first bool) (string, bool) {
+	var parts StenoParts
+	for _, phoneme := range onset {
+		chord, ok := phonemeLeftChord(phoneme)
+		if !ok {
+			return "", false
+		}
+		parts.Left += chord
+	}
+	if !first && len(onset) == 0 {
+		parts.Left = "KWR"
+	}
+	parts.Vowels = vowels
+	for _, phoneme := range coda {
+		chord, ok := phonemeRightChord(phoneme)
+		if !ok {
+			return "", false
+		}
+		if strings.HasPrefix(chord, "*") {
+			chord = chord[1:]
+			if !strings.Contains(parts.Vowels, "*") {
+				parts.Vowels = withAsterisk(parts.Vowels)
+			}
+		}
+		parts.Right += chord
+	}
+	return validMergedStroke(parts)
+}
+
+// proposeOutlines builds Lapwing style outlines for a pronunciation of word, one stroke per syllable,
+// trying every legal way to split the consonants between syllables and every vowel bank for each
+// vowel. Only the best maxProposalCandidates outlines by rankProposals are kept.
+func proposeOutlines(word string, phonemes []string) []string {
+	syllables := syllabify(phonemes)
+	if len(syllables) == 0 || len(syllables) > maxProposalSyllables {
+		return nil
+	}
+	var outlines []string
+	var build func(i int, onset []string, strokes []string)
+	build = func(i int, onset []string, strokes []string) {
+		if i == len(syllables) {
+			outlines = append(outlines, strings.Join(strokes, "/"))
+			// rank every so often so the worst outlines don't pile up
+			if len(outlines) >= 2*maxProposalCandidates {
+				ranked := rankProposals(word, outlines)
+				outlines = ranked[:min(len(ranked), maxProposalCandidates)]
+			}
+			return
+		}
+		// the consonants up to the next vowel can be split between this syllable's coda and the next onset
+		consonants := syllables[i].coda
+		if i+1 < len(syllables) {
+			consonants = append(slices.Clone(syllables[i].coda), syllables[i+1].onset...)
+		}
+		onsetLengths := []int{0}
+		if i+1 < len(syllables) {
+			onsetLengths = legalBoundaryLengths(consonants)
+		}
+		vowelOptions, rightPrefix := vowelChordOptions(syllables[i].nucleus)
+		for _, onsetLength := range onsetLengths {
+			coda := consonants[:len(consonants)-onsetLength]
+			if rightPrefix != "" {
+				coda = append([]string{rightPrefix}, coda...)
+			}
+			nextOnset := consonants[len(consonants)-onsetLength:]
+			for _, vowels := range vowelOptions {
+				if stroke, ok := proposedStroke(onset, vowels, coda, i == 0); ok {
+					build(i+1, nextOnset, append(strokes[:len(strokes):len(strokes)], stroke))
+				}
+			}
+		}
+	}
+	build(0, syllables[0].onset, nil)
+	ranked := rankProposals(word, outlines)
+	return ranked[:min(len(ranked), maxProposalCandidates)]
+}
+
+// rankProposals orders outlines by how well their chords spell the word, then by the fewest strokes
+// and keys
+func rankProposals(word string, outlines []string) []string {
+	scores := make(map[string]float64, len(outlines))
+	for _, outline := range outlines {
+		scores[outline] = outlineAlignmentScore(outline, word)
+	}
+	ranked := slices.Clone(outlines)
+	slices.SortFunc(ranked, func(a, b string) int {
+		return cmp.Or(
+			cmp.Compare(scores[b], scores[a]),
+			cmp.Compare(strings.Count(a, "/"), strings.Count(b, "/")),
+			cmp.Compare(stenoKeyCount(a), stenoKeyCount(b)),
+			cmp.Compare(a, b),
+		)
+	})
+	return slices.Compact(ranked)
+}
+
+// proposal is the outcome of proposing outlines for one word
+type proposal struct {
+	word     string
+	outlines []string
+	// why no outline was proposed
+	reason string
+}
+
+// proposeWords adds up to maxProposals outlines for every word, checked against the dictionaries like
+// generated entries are
+func proposeWords(words []string, pronunciations *PronunciationDictionary, maxProposals int, ctx *augmentationContext) []proposal {
+	translations := make(map[string]bool)
+	for _, dict := range []*map[string]string{ctx.originalDictionary, ctx.additionalEntries} {
+		for _, value := range *dict {
+			translations[value] = true
+		}
+	}
+	var proposals []proposal
+	for _, word := range words {
+		result := proposal{word: word}
+		if translations[word] {
+			result.reason = "already in the dictionaries"
+			proposals = append(proposals, result)
+			continue
+		}
+		if !pronunciations.knowsWord(word) {
+			result.reason = "no pronunciation"
+			proposals = append(proposals, result)
+			continue
+		}
+		var candidates []string
+		for _, phonemes := range pronunciations.lookup(word) {
+			candidates = append(candidates, proposeOutlines(word, phonemes)...)
+		}
+		if len(candidates) == 0 {
+			result.reason = "no pronunciation that can be written in one stroke per syllable"
+			proposals = append(proposals, result)
+			continue
+		}
+		for _, outline := range rankProposals(word, candidates) {
+			if len(result.outlines) == maxProposals {
+				break
+			}
+			if addEntryIfNotPresent(outline, word, derivation{rule: ruleProposed}, ctx) {
+				result.outlines = append(result.outlines, ctx.addedOutline(outline, word))
+			}
+		}
+		if len(result.outlines) == 0 {
+			result.reason = "every outline conflicts with the dictionaries"
+			if rejection, ok := ctx.rejectionFor(rankProposals(word, candidates)[0], word); ok {
+				result.reason += ", e.g. " + rejection.reason
+			}
+		}
+		proposals = append(proposals, result)
+	}
+	return proposals
+}
+
+// readWordList reads one word per line, skipping blank lines and # comments
+func readWordList(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var words []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			words = append(words, line)
+		}
+	}
+	return words, scanner.Err()
+}
+
+func runPropose(args []string) {
+	logger := log.New(os.Stderr, "LOG: ", log.LstdFlags|log.Lmicroseconds)
+	flags := flag.NewFlagSet("propose", flag.ExitOnError)
+	var (
+		sourceDictPaths stringList
+		targetDictPaths stringList
+	)
+	flags.Var(&sourceDictPaths, "lapwing_source", "source dictionary path(s)")
+	flags.Var(&targetDictPaths, "output_target", "path(s) to write the proposed entries to")
+	wordsPath := flags.String("words", "", "file with one word to propose outlines for per line")
+	maxProposals := flags.Int("max_proposals", 3, "maximum number of outlines to propose per word")
+	augmentationFlags := registerAugmentationFlags(flags)
+	flags.Parse(args)
+
+	if *wordsPath == "" || len(sourceDictPaths) == 0 || len(targetDictPaths) == 0 {
+		fmt.Println("Usage: lapwing_augmentor propose --words <words> --pronunciations <cmudict> --lapwing_source <source-dict> [--lapwing_source <source-dict2> ...] " +
+			"--output_target <target-dict> [--max_proposals <n>]")
+		os.Exit(1)
+	}
+	options, err := augmentationFlags.load(logger)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	if options.pronunciations == nil {
+		fmt.Println("propose needs a --pronunciations file")
+		os.Exit(1)
+	}
+	options.recordRejections = true
+	words, err := readWordList(*wordsPath)
+	if err != nil {
+		fmt.Println("Error reading words:", err)
+		os.Exit(1)
+	}
+
+	originalDictionary := make(map[string]string)
+	for _, sourceDictPath := range sourceDictPaths {
+		if err := readDictionaryInto(sourceDictPath, originalDictionary); err != nil {
+			fmt.Println("Error reading source dictionary:", err)
+			os.Exit(1)
+		}
+	}
+	// proposals have to fit in with the generated entries as well as the source dictionaries
+	ctx := augmentDictionary(originalDictionary, options, logger)
+
+	proposedEntries := make(map[string]string)
+	for _, proposal := range proposeWords(words, options.pronunciations, *maxProposals, ctx) {
+		if len(proposal.outlines) == 0 {
+			fmt.Printf("%s: %s\n", proposal.word, proposal.reason)
+			continue
+		}
+		fmt.Printf("%s: %s\n", proposal.word, strings.Join(proposal.outlines, ", "))
+		for _, outline := range proposal.outlines {
+			proposedEntries[outline] = proposal.word
+		}
+	}
+	for _, targetPath := range targetDictPaths {
+		if err := writeDictionary(targetPath, proposedEntries); err != nil {
+			fmt.Println("Error writing to target dictionary:", err)
+			os.Exit(1)
+		}
+	}
+}
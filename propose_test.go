@@ -0,0 +1,139 @@
+package main
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestPhonemeLeftChord(t *testing.T) {
+	tests := []struct {
+		phoneme  string
+		expected string
+		ok       bool
+	}{
+		{"JH", "SKWR", true},
+		{"ZH", "SH", true},
+		{"DH", "TH", true},
+		{"HH", "H", true},
+		{"NG", "", false},
+		{"AE", "", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.phoneme, func(t *testing.T) {
+			chord, ok := phonemeLeftChord(test.phoneme)
+			if chord != test.expected || ok != test.ok {
+				t.Errorf("expected %q %v, got %q %v", test.expected, test.ok, chord, ok)
+			}
+		})
+	}
+}
+
+func TestPhonemeRightChord(t *testing.T) {
+	tests := []struct {
+		phoneme  string
+		expected string
+		ok       bool
+	}{
+		{"T", "T", true},
+		{"K", "BG", true},
+		{"N", "PB", true},
+		{"NG", "PBG", true},
+		{"TH", "*T", true},
+		{"V", "F", true},
+		{"HH", "", false},
+		{"W", "", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.phoneme, func(t *testing.T) {
+			chord, ok := phonemeRightChord(test.phoneme)
+			if chord != test.expected || ok != test.ok {
+				t.Errorf("expected %q %v, got %q %v", test.expected, test.ok, chord, ok)
+			}
+		})
+	}
+}
+
+func TestProposedStroke(t *testing.T) {
+	tests := []struct {
+		name     string
+		onset    []string
+		vowels   string
+		coda     []string
+		first    bool
+		expected string
+		ok       bool
+	}{
+		{"consonants on both sides", []string{"K"}, "A", []string{"T"}, true, "KAT", true},
+		{"onset cluster", []string{"S", "T", "R"}, "EU", []string{"NG"}, true, "STREUPBG", true},
+		{"glide without an onset", nil, "AOE", nil, false, "KWRAOE", true},
+		{"no glide at the start", nil, "A", []string{"T"}, true, "AT", true},
+		{"asterisk for th", []string{"M"}, "U", []string{"TH"}, true, "PH*UT", true},
+		{"out of steno order", []string{"L", "P"}, "A", nil, true, "", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stroke, ok := proposedStroke(test.onset, test.vowels, test.coda, test.first)
+			if stroke != test.expected || ok != test.ok {
+				t.Errorf("expected %q %v, got %q %v", test.expected, test.ok, stroke, ok)
+			}
+		})
+	}
+}
+
+func TestProposeOutlines(t *testing.T) {
+	outlines := proposeOutlines("happy", strings.Fields("HH AE1 P IY0"))
+	for _, expected := range []string{"HA/PAOE", "HAP/KWRAOE"} {
+		if !slices.Contains(outlines, expected) {
+			t.Errorf("expected %s among %v", expected, outlines)
+		}
+	}
+	for _, outline := range outlines {
+		if strings.Count(outline, "/") != 1 {
+			t.Errorf("expected one stroke per syllable, got %s", outline)
+		}
+	}
+	if ranked := rankProposals("happy", outlines); !slices.Equal(outlines, ranked) {
+		t.Errorf("expected the outlines to be ranked, got %v", outlines)
+	}
+}
+
+func TestProposeWords(t *testing.T) {
+	ctx := newTestAugmentationContext(map[string]string{
+		"KAT":    "cat",
+		"HA":     "ha",
+		"PAOE":   "pea",
+		"PAOEUT": "pate",
+	})
+	ctx.rejections = make(map[string][]rejection)
+	pronunciations := &PronunciationDictionary{pronunciations: map[string][][]string{
+		"happy": {strings.Fields("HH AE1 P IY0")},
+		"cat":   {strings.Fields("K AE1 T")},
+	}}
+
+	proposals := proposeWords([]string{"happy", "cat", "syzygy"}, pronunciations, 2, ctx)
+
+	if len(proposals) != 3 {
+		t.Fatalf("expected 3 proposals, got %v", proposals)
+	}
+	happy := proposals[0]
+	if len(happy.outlines) != 2 || slices.Contains(happy.outlines, "HA/PAOE") {
+		t.Errorf("expected 2 outlines for happy without the ha pea conflict, got %v", happy.outlines)
+	}
+	for _, outline := range happy.outlines {
+		if (*ctx.additionalEntries)[outline] != "happy" || ctx.provenance[outline].Rule != ruleProposed {
+			t.Errorf("expected %s to be a proposed entry for happy", outline)
+		}
+	}
+	expected := []proposal{
+		{word: "cat", reason: "already in the dictionaries"},
+		{word: "syzygy", reason: "no pronunciation"},
+	}
+	if !reflect.DeepEqual(proposals[1:], expected) {
+		t.Errorf("expected %v, got %v", expected, proposals[1:])
+	}
+}
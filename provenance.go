@@ -0,0 +1,219 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"os"
+	"slices"
+	"strings"
+)
+
+// rules that generate entries, used in the provenance output
+const (
+	ruleStrokeOmission    = "stroke-omission"
+	ruleAlternateSplit    = "alternate-split"
+	ruleKwrRemoval        = "kwr-removal"
+	ruleKwrAddition       = "kwr-addition"
+	ruleWGlideRemoval     = "w-glide-removal"
+	ruleWGlideAddition    = "w-glide-addition"
+	ruleDashStrokeFold    = "dash-stroke-fold"
+	ruleLeftStrokeFold    = "left-stroke-fold"
+	ruleSuffixFold        = "suffix-fold"
+	rulePrefixFold        = "prefix-fold"
+	ruleInflection        = "inflection"
+	ruleInflectionFamily  = "inflection-family"
+	ruleCompound          = "compound"
+	ruleProposed          = "proposed"
+	ruleKwreuVowel        = "kwreu-vowel"
+	rulePrefixReplacement = "prefix-replacement"
+	ruleSuffixReplacement = "suffix-replacement"
+	ruleStringReplacement = "string-replacement"
+	ruleLongO             = "long-o"
+	ruleFinalEUToAOE      = "final-eu-aoe"
+	ruleInitialKHToKPH    = "initial-kh-kph"
+	ruleSZFold            = "sz-fold"
+	ruleCaseVariant       = "case-variant"
+	// entries of an output target that were kept from manual edits rather than generated
+	ruleManualEdit = "manual-edit"
+)
+
+// weights of the parts of a candidate's score
+const (
+	baseCandidateScore    = 10.0
+	defaultRuleCost       = 1.0
+	strokeSavedWeight     = 2.0
+	keyPressedWeight      = 0.25
+	derivationDepthWeight = 1.0
+	nearMissSegmentWeight = 1.5
+)
+
+// ruleCosts are how much a rule lowers a candidate's score. Rules that change how a word is split or
+// drop parts of it are riskier than ones that only swap a chord for an equivalent one.
+var ruleCosts = map[string]float64{
+	ruleStrokeOmission: 3,
+	ruleAlternateSplit: 2,
+	ruleKwrRemoval:     1,
+	ruleKwrAddition:    2,
+	// the W glide is used less consistently than KWR
+	ruleWGlideRemoval:     1.5,
+	ruleWGlideAddition:    2.5,
+	ruleDashStrokeFold:    0.5,
+	ruleLeftStrokeFold:    0.5,
+	ruleSuffixFold:        0.5,
+	rulePrefixFold:        0.5,
+	ruleInflection:        1,
+	ruleInflectionFamily:  1,
+	ruleCompound:          1,
+	ruleProposed:          1,
+	ruleKwreuVowel:        1,
+	rulePrefixReplacement: 1,
+	ruleSuffixReplacement: 1,
+	ruleStringReplacement: 1.5,
+	ruleLongO:             1,
+	ruleFinalEUToAOE:      1,
+	ruleInitialKHToKPH:    0.5,
+	ruleSZFold:            0.5,
+	ruleCaseVariant:       0,
+}
+
+// derivation is the rule that generated a candidate and the outline it was generated from
+type derivation struct {
+	rule   string
+	source string
+}
+
+// provenanceRecord describes where a generated entry came from
+type provenanceRecord struct {
+	Outline     string  `json:"outline"`
+	Translation string  `json:"translation"`
+	Rule        string  `json:"rule"`
+	Source      string  `json:"source"`
+	Depth       int     `json:"depth"`
+	Score       float64 `json:"score"`
+	StrokeCost  float64 `json:"stroke_cost"`
+	Harder      bool    `json:"harder_than_source"`
+	// set on asterisk variants added because the outline without the asterisk belongs to ConflictsWith
+	AsteriskFor   string `json:"asterisk_for,omitempty"`
+	ConflictsWith string `json:"conflicts_with,omitempty"`
+}
+
+// stenoKeyCount counts the keys pressed to write an outline
+func stenoKeyCount(key string) int {
+	count := 0
+	for _, r := range key {
+		if r != '/' && r != '-' {
+			count++
+		}
+	}
+	return count
+}
+
+// nearMissSegmentations counts the places an outline can be cut so that the strokes on one side are
+// already an entry, which is where word boundary errors tend to come from
+func nearMissSegmentations(key string, ctx *augmentationContext) int {
+	strokes := strings.Split(key, "/")
+	count := 0
+	for i := 1; i < len(strokes); i++ {
+		prefix := strings.Join(strokes[:i], "/")
+		suffix := strings.Join(strokes[i:], "/")
+		if ctx.hasEntry(prefix) || ctx.hasEntry(suffix) {
+			count++
+		}
+	}
+	return count
+}
+
+func (ctx *augmentationContext) hasEntry(key string) bool {
+	return hasKey(key, ctx.originalDictionary) || hasKey(key, ctx.additionalEntries)
+}
+
+// derivationDepth is the number of rules applied since an entry of the original dictionary
+func (ctx *augmentationContext) derivationDepth(origin derivation) int {
+	if record, ok := ctx.provenance[origin.source]; ok {
+		return record.Depth + 1
+	}
+	return 1
+}
+
+// candidateScore rates a generated outline, higher is better. Saving strokes raises the score, while
+// extra keys, costly rules, long derivation chains and near-miss segmentations lower it.
+func candidateScore(key string, origin derivation, depth int, ctx *augmentationContext) float64 {
+	ruleCost, ok := ruleCosts[origin.rule]
+	if !ok {
+		ruleCost = defaultRuleCost
+	}
+	score := baseCandidateScore - ruleCost - derivationDepthWeight*float64(depth-1)
+	if origin.source != "" {
+		strokesSaved := strings.Count(origin.source, "/") - strings.Count(key, "/")
+		keysAdded := stenoKeyCount(key) - stenoKeyCount(origin.source)
+		score += strokeSavedWeight*float64(strokesSaved) - keyPressedWeight*float64(keysAdded)
+	}
+	score -= nearMissSegmentWeight * float64(nearMissSegmentations(key, ctx))
+	return score
+}
+
+// outputProvenance describes every entry of an output target, generated entries by the rule that
+// generated them and entries kept from manual edits by ruleManualEdit
+func outputProvenance(entries map[string]string, ctx *augmentationContext) map[string]provenanceRecord {
+	records := make(map[string]provenanceRecord, len(entries))
+	for key, value := range entries {
+		record, ok := ctx.provenance[key]
+		if generated, isGenerated := (*ctx.additionalEntries)[key]; !isGenerated || generated != value {
+			record = provenanceRecord{Outline: key, Rule: ruleManualEdit}
+		} else if !ok {
+			record = provenanceRecord{Outline: key}
+		}
+		record.Translation = value
+		records[key] = record
+	}
+	return records
+}
+
+// writeProvenance writes the provenance of every entry of an output target, highest score first
+func writeProvenance(path string, entries map[string]string, ctx *augmentationContext) error {
+	records := make([]provenanceRecord, 0, len(entries))
+	for _, record := range outputProvenance(entries, ctx) {
+		records = append(records, record)
+	}
+	slices.SortFunc(records, func(a, b provenanceRecord) int {
+		if a.Score != b.Score {
+			return cmp.Compare(b.Score, a.Score)
+		}
+		return cmp.Compare(a.Outline, b.Outline)
+	})
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// readProvenance reads a file written by writeProvenance, keyed by outline
+func readProvenance(path string) (map[string]provenanceRecord, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var records []provenanceRecord
+	if err := json.Unmarshal(contents, &records); err != nil {
+		return nil, err
+	}
+	provenance := make(map[string]provenanceRecord, len(records))
+	for _, record := range records {
+		provenance[record.Outline] = record
+	}
+	return provenance, nil
+}
+
+// readOptionalProvenance reads a provenance file, or returns no records if path is empty
+func readOptionalProvenance(path string) (map[string]provenanceRecord, error) {
+	if path == "" {
+		return make(map[string]provenanceRecord), nil
+	}
+	return readProvenance(path)
+}
+
+func (r provenanceRecord) derivation() derivation {
+	return derivation{rule: r.Rule, source: r.Source}
+}
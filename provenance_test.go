@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newTestAugmentationContext(originalDictionary map[string]string) *augmentationContext {
+	additionalEntries := make(map[string]string)
+	return &augmentationContext{
+		originalDictionary:   &originalDictionary,
+		additionalEntries:    &additionalEntries,
+		prefixTree:           NewPrefixTree(),
+		ignoredChordPatterns: &map[string]bool{},
+		alternativeCounts:    make(map[string]int),
+		minScore:             math.Inf(-1),
+		provenance:           make(map[string]provenanceRecord),
+		strokeCosts:          DefaultStrokeCostModel(),
+	}
+}
+
+func TestCandidateScore(t *testing.T) {
+	ctx := newTestAugmentationContext(map[string]string{
+		"HAP":       "hap",
+		"HAP/KWREU": "happy",
+		"TPHUS":     "nuss",
+	})
+
+	tests := []struct {
+		name   string
+		key    string
+		origin derivation
+		depth  int
+		want   float64
+	}{
+		{name: "stroke saved", key: "HAPZ", origin: derivation{rule: ruleSZFold, source: "HAP/-Z"}, depth: 1, want: 10 - 0.5 + 2},
+		{name: "extra keys", key: "KPHAT", origin: derivation{rule: ruleInitialKHToKPH, source: "KHAT"}, depth: 1, want: 10 - 0.5 - 0.25},
+		{name: "deeper derivation", key: "KPHAT", origin: derivation{rule: ruleInitialKHToKPH, source: "KHAT"}, depth: 3, want: 10 - 0.5 - 0.25 - 2},
+		{name: "near miss segmentation", key: "HAP/PEU", origin: derivation{rule: ruleAlternateSplit, source: "HA/PEU"}, depth: 1, want: 10 - 2 - 0.25 - 1.5},
+		{name: "unknown rule", key: "TKPWOE", origin: derivation{rule: "other", source: "TKPWOE"}, depth: 1, want: 9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := candidateScore(tt.key, tt.origin, tt.depth, ctx)
+			if got != tt.want {
+				t.Fatalf("candidateScore(%q) = %v, want %v", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddEntryIfNotPresentMinScore(t *testing.T) {
+	ctx := newTestAugmentationContext(map[string]string{"KAT": "cat", "KAT/-S": "cats"})
+	ctx.minScore = 8.5
+
+	if addEntryIfNotPresent("KPHAT", "chat", derivation{rule: ruleStrokeOmission, source: "KHAT"}, ctx) {
+		t.Fatal("expected KPHAT to score below the minimum")
+	}
+	if !addEntryIfNotPresent("KATS", "cats", derivation{rule: ruleSZFold, source: "KAT/-S"}, ctx) {
+		t.Fatal("expected KATS to be added")
+	}
+	if !addEntryIfNotPresent("KA*TS", "Cats", derivation{rule: ruleCaseVariant, source: "KATS"}, ctx) {
+		t.Fatal("expected KA*TS to be added")
+	}
+	record := ctx.provenance["KA*TS"]
+	if record.Rule != ruleCaseVariant || record.Source != "KATS" || record.Depth != 2 {
+		t.Fatalf("unexpected provenance %+v", record)
+	}
+}
+
+func TestWriteProvenance(t *testing.T) {
+	ctx := newTestAugmentationContext(map[string]string{"KAT/-S": "cats", "KHAT": "chat"})
+	addEntryIfNotPresent("KPHAT", "chat", derivation{rule: ruleInitialKHToKPH, source: "KHAT"}, ctx)
+	addEntryIfNotPresent("KATS", "cats", derivation{rule: ruleSZFold, source: "KAT/-S"}, ctx)
+
+	// the written output keeps a manual edit of KPHAT and a manually added entry
+	output := map[string]string{"KPHAT": "chatted", "KATS": "cats", "KAT": "cat"}
+	path := filepath.Join(t.TempDir(), "provenance.json")
+	if err := writeProvenance(path, output, ctx); err != nil {
+		t.Fatal(err)
+	}
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var records []provenanceRecord
+	if err := json.Unmarshal(contents, &records); err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 || records[0].Outline != "KATS" || records[0].Rule != ruleSZFold {
+		t.Fatalf("expected records sorted by score, got %+v", records)
+	}
+	for _, record := range records[1:] {
+		if record.Rule != ruleManualEdit || record.Translation != output[record.Outline] {
+			t.Errorf("expected %s to be a manual edit, got %+v", record.Outline, record)
+		}
+	}
+}
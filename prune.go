@@ -0,0 +1,273 @@
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const (
+	unknownRule = "unknown"
+	undoStroke  = "*"
+)
+
+// matches the steno of a logged stroke, e.g. "Stroke(KAT : ['K-', 'A-', '-T'])"
+var strokeLogRegex = regexp.MustCompile(`Stroke\(([^ :)]+)`)
+
+// parseStrokeLogEvents reads the strokes of a Plover strokes.log in order, undo strokes included
+func parseStrokeLogEvents(reader io.Reader) ([]string, error) {
+	var strokes []string
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if match := strokeLogRegex.FindStringSubmatch(scanner.Text()); match != nil {
+			strokes = append(strokes, match[1])
+		}
+	}
+	return strokes, scanner.Err()
+}
+
+// longestOutlineLength is the number of strokes of the longest outline in the dictionaries
+func longestOutlineLength(dicts ...*map[string]string) int {
+	longest := 1
+	for _, dict := range dicts {
+		for key := range *dict {
+			longest = max(longest, strings.Count(key, "/")+1)
+		}
+	}
+	return longest
+}
+
+// strokeTranslator splits strokes into outlines as they come in, the way Plover does: each stroke is
+// joined with the outlines before it when together they make the longest outline found in any of the
+// dictionaries. An undo stroke takes back the whole last outline, not just its last stroke.
+type strokeTranslator struct {
+	maxOutlineLength int
+	dicts            []*map[string]string
+	outlines         [][]string
+}
+
+func newStrokeTranslator(maxOutlineLength int, dicts ...*map[string]string) *strokeTranslator {
+	return &strokeTranslator{maxOutlineLength: maxOutlineLength, dicts: dicts}
+}
+
+// write adds a stroke, joining it with the outlines before it if that makes an outline
+func (t *strokeTranslator) write(stroke string) {
+	// the most outlines before the stroke that still fit in the longest outline
+	joined, length := 0, 1
+	for joined < len(t.outlines) && length+len(t.outlines[len(t.outlines)-1-joined]) <= t.maxOutlineLength {
+		length += len(t.outlines[len(t.outlines)-1-joined])
+		joined++
+	}
+	outline := []string{stroke}
+	for ; joined > 0; joined-- {
+		var candidate []string
+		for _, previous := range t.outlines[len(t.outlines)-joined:] {
+			candidate = append(candidate, previous...)
+		}
+		candidate = append(candidate, stroke)
+		if hasKeyInAny(strings.Join(candidate, "/"), t.dicts...) {
+			outline = candidate
+			t.outlines = t.outlines[:len(t.outlines)-joined]
+			break
+		}
+	}
+	t.outlines = append(t.outlines, outline)
+}
+
+// undo takes back the last outline and returns its strokes, or nil if nothing is left to undo
+func (t *strokeTranslator) undo() []string {
+	if len(t.outlines) == 0 {
+		return nil
+	}
+	outline := t.outlines[len(t.outlines)-1]
+	t.outlines = t.outlines[:len(t.outlines)-1]
+	return outline
+}
+
+// translateStrokes splits a stroke log into the outlines left after its undo strokes, see
+// strokeTranslator
+func translateStrokes(events []string, maxOutlineLength int, dicts ...*map[string]string) [][]string {
+	translator := newStrokeTranslator(maxOutlineLength, dicts...)
+	for _, stroke := range events {
+		if stroke == undoStroke {
+			translator.undo()
+		} else {
+			translator.write(stroke)
+		}
+	}
+	return translator.outlines
+}
+
+func hasKeyInAny(key string, dicts ...*map[string]string) bool {
+	for _, dict := range dicts {
+		if hasKey(key, dict) {
+			return true
+		}
+	}
+	return false
+}
+
+// countOutlineUsage splits the strokes of a stroke log into outlines with translateStrokes and
+// counts how often each generated outline was written
+func countOutlineUsage(events []string, originalDictionary, additionalEntries *map[string]string) map[string]int {
+	maxOutlineLength := longestOutlineLength(originalDictionary, additionalEntries)
+	usage := make(map[string]int)
+	for _, strokes := range translateStrokes(events, maxOutlineLength, originalDictionary, additionalEntries) {
+		outline := strings.Join(strokes, "/")
+		if !hasKey(outline, originalDictionary) && hasKey(outline, additionalEntries) {
+			usage[outline]++
+		}
+	}
+	return usage
+}
+
+// ruleUsage is how many entries a rule generated, how many of them were written and how often
+type ruleUsage struct {
+	rule        string
+	entries     int
+	usedEntries int
+	hits        int
+}
+
+func ruleUsageCounts(additionalEntries *map[string]string, usage map[string]int, provenance map[string]provenanceRecord) []ruleUsage {
+	counts := make(map[string]*ruleUsage)
+	for key := range *additionalEntries {
+		rule := unknownRule
+		if record, ok := provenance[key]; ok && record.Rule != "" {
+			rule = record.Rule
+		}
+		count, ok := counts[rule]
+		if !ok {
+			count = &ruleUsage{rule: rule}
+			counts[rule] = count
+		}
+		count.entries++
+		if usage[key] > 0 {
+			count.usedEntries++
+			count.hits += usage[key]
+		}
+	}
+
+	var result []ruleUsage
+	for _, count := range counts {
+		result = append(result, *count)
+	}
+	slices.SortFunc(result, func(a, b ruleUsage) int {
+		if a.hits != b.hits {
+			return cmp.Compare(b.hits, a.hits)
+		}
+		return cmp.Compare(a.rule, b.rule)
+	})
+	return result
+}
+
+// prunedEntries keeps the generated entries that were written plus every entry of a rule written at
+// least minRuleHits times
+func prunedEntries(additionalEntries *map[string]string, usage map[string]int, provenance map[string]provenanceRecord,
+	rules []ruleUsage, minRuleHits int) map[string]string {
+	keptRules := make(map[string]bool)
+	for _, rule := range rules {
+		if minRuleHits > 0 && rule.hits >= minRuleHits {
+			keptRules[rule.rule] = true
+		}
+	}
+
+	pruned := make(map[string]string)
+	for key, value := range *additionalEntries {
+		rule := unknownRule
+		if record, ok := provenance[key]; ok && record.Rule != "" {
+			rule = record.Rule
+		}
+		if usage[key] > 0 || keptRules[rule] {
+			pruned[key] = value
+		}
+	}
+	return pruned
+}
+
+func runPrune(args []string) {
+	flags := flag.NewFlagSet("prune", flag.ExitOnError)
+	var (
+		strokeLogPaths  stringList
+		sourceDictPaths stringList
+		targetDictPaths stringList
+	)
+	flags.Var(&strokeLogPaths, "stroke_log", "Plover strokes.log path(s)")
+	flags.Var(&sourceDictPaths, "lapwing_source", "source dictionary path(s) the augmentations were generated from")
+	flags.Var(&targetDictPaths, "output_target", "path(s) to write the pruned augmentations to")
+	augmentationsPath := flags.String("augmentations", "", "generated dictionary to prune")
+	provenancePath := flags.String("provenance", "", "optional provenance file written when the augmentations were generated, used to count hits per rule")
+	minRuleHits := flags.Int("min_rule_hits", 0, "also keep every entry of a rule written at least this many times, 0 to only keep written entries")
+	flags.Parse(args)
+
+	if len(strokeLogPaths) == 0 || *augmentationsPath == "" {
+		fmt.Println("Usage: lapwing_augmentor prune --stroke_log <strokes.log> [--stroke_log <strokes.log> ...] --augmentations <augmentations> " +
+			"[--lapwing_source <source-dict> ...] [--provenance <provenance>] [--min_rule_hits <n>] [--output_target <target-dict> ...]")
+		os.Exit(1)
+	}
+
+	originalDictionary := make(map[string]string)
+	for _, sourceDictPath := range sourceDictPaths {
+		if err := readDictionaryInto(sourceDictPath, originalDictionary); err != nil {
+			fmt.Println("Error reading source dictionary:", err)
+			os.Exit(1)
+		}
+	}
+	additionalEntries := make(map[string]string)
+	if err := readDictionaryInto(*augmentationsPath, additionalEntries); err != nil {
+		fmt.Println("Error reading augmentations:", err)
+		os.Exit(1)
+	}
+	provenance, err := readOptionalProvenance(*provenancePath)
+	if err != nil {
+		fmt.Println("Error reading provenance:", err)
+		os.Exit(1)
+	}
+
+	// logs are matched one at a time so outlines aren't joined across files
+	usage := make(map[string]int)
+	for _, strokeLogPath := range strokeLogPaths {
+		file, err := os.Open(strokeLogPath)
+		if err != nil {
+			fmt.Println("Error reading stroke log:", err)
+			os.Exit(1)
+		}
+		events, err := parseStrokeLogEvents(file)
+		file.Close()
+		if err != nil {
+			fmt.Println("Error reading stroke log:", err)
+			os.Exit(1)
+		}
+		for key, count := range countOutlineUsage(events, &originalDictionary, &additionalEntries) {
+			usage[key] += count
+		}
+	}
+
+	rules := ruleUsageCounts(&additionalEntries, usage, provenance)
+	fmt.Printf("%-20s %8s %8s %8s\n", "rule", "entries", "used", "hits")
+	for _, rule := range rules {
+		fmt.Printf("%-20s %8d %8d %8d\n", rule.rule, rule.entries, rule.usedEntries, rule.hits)
+	}
+
+	pruned := prunedEntries(&additionalEntries, usage, provenance, rules, *minRuleHits)
+	contents, err := json.MarshalIndent(pruned, "", "  ")
+	if err != nil {
+		fmt.Println("Error marshalling JSON:", err)
+		os.Exit(1)
+	}
+	for _, targetPath := range targetDictPaths {
+		if err := os.WriteFile(targetPath, contents, 0644); err != nil {
+			fmt.Println("Error writing to target dictionary:", err)
+			os.Exit(1)
+		}
+		fmt.Println("Wrote", len(pruned), "of", len(additionalEntries), "additional entries to", targetPath)
+	}
+}
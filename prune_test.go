@@ -0,0 +1,80 @@
+package main
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseStrokeLog(t *testing.T) {
+	log := `2024-01-02 10:00:00,001 Stroke(KAT : ['K-', 'A-', '-T'])
+2024-01-02 10:00:00,002 Translation(('KAT',) : cat)
+2024-01-02 10:00:01,000 Stroke(TKOG : ['T-', 'K-', 'O-', '-G'])
+2024-01-02 10:00:01,500 Stroke(* : ['*'])
+2024-01-02 10:00:02,000 Stroke(TKOGS : ['T-', 'K-', 'O-', '-G', '-S'])
+`
+	events, err := parseStrokeLogEvents(strings.NewReader(log))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"KAT", "TKOG", "*", "TKOGS"}; !slices.Equal(events, want) {
+		t.Fatalf("parseStrokeLogEvents() = %q, want %q", events, want)
+	}
+}
+
+func TestCountOutlineUsage(t *testing.T) {
+	originalDictionary := map[string]string{"KAT": "cat", "KAT/-S": "cats", "HAP": "hap", "HAP/KWREU": "happy"}
+	additionalEntries := map[string]string{"KATS": "cats", "HAP/PEU": "happy", "HAPZ": "haps"}
+	tests := []struct {
+		name   string
+		events string
+		want   map[string]int
+	}{
+		{name: "longest outlines", events: "KATS HAP KWREU HAP PEU KAT -S KATS", want: map[string]int{"KATS": 2, "HAP/PEU": 1}},
+		{name: "undo takes back a single stroke outline", events: "KATS * KAT -S", want: map[string]int{}},
+		// HAP would be left behind to join PEU if only the last stroke was taken back
+		{name: "undo takes back the whole outline", events: "HAP KWREU * PEU", want: map[string]int{}},
+		{name: "undo only takes back the last outline", events: "HAPZ HAP PEU *", want: map[string]int{"HAPZ": 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := countOutlineUsage(strings.Fields(tt.events), &originalDictionary, &additionalEntries)
+			if !maps.Equal(got, tt.want) {
+				t.Fatalf("countOutlineUsage(%s) = %v, want %v", tt.events, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrunedEntries(t *testing.T) {
+	additionalEntries := map[string]string{"KATS": "cats", "KATZ": "cats", "HAP/PEU": "happy", "HA/PEU": "happy", "HAPZ": "haps"}
+	provenance := map[string]provenanceRecord{
+		"KATS":    {Outline: "KATS", Rule: ruleSZFold},
+		"KATZ":    {Outline: "KATZ", Rule: ruleSZFold},
+		"HAP/PEU": {Outline: "HAP/PEU", Rule: ruleAlternateSplit},
+		"HA/PEU":  {Outline: "HA/PEU", Rule: ruleAlternateSplit},
+	}
+	usage := map[string]int{"KATS": 5, "HAP/PEU": 1}
+
+	rules := ruleUsageCounts(&additionalEntries, usage, provenance)
+	wantRules := []ruleUsage{
+		{rule: ruleSZFold, entries: 2, usedEntries: 1, hits: 5},
+		{rule: ruleAlternateSplit, entries: 2, usedEntries: 1, hits: 1},
+		{rule: unknownRule, entries: 1},
+	}
+	if !slices.Equal(rules, wantRules) {
+		t.Fatalf("ruleUsageCounts() = %+v, want %+v", rules, wantRules)
+	}
+
+	pruned := prunedEntries(&additionalEntries, usage, provenance, rules, 0)
+	got := sortedMapKeys(&pruned)
+	if want := []string{"KATS", "HAP/PEU"}; !slices.Equal(got, want) {
+		t.Fatalf("prunedEntries() without rule hits = %q, want %q", got, want)
+	}
+	pruned = prunedEntries(&additionalEntries, usage, provenance, rules, 3)
+	got = sortedMapKeys(&pruned)
+	if want := []string{"KATS", "KATZ", "HAP/PEU"}; !slices.Equal(got, want) {
+		t.Fatalf("prunedEntries() with rule hits = %q, want %q", got, want)
+	}
+}
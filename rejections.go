@@ -0,0 +1,45 @@
+package main
+
+// reasons a candidate is dropped
+const (
+	rejectedWordBoundary      = "creates a word boundary conflict"
+	rejectedFinalWordBoundary = "creates a word boundary conflict with entries generated later"
+	rejectedStenoOrder        = "a stroke is not in steno order"
+	rejectedAlignment         = "chords don't spell the word well enough for --min_alignment"
+	rejectedMaxAlternatives   = "word already has --max_alternatives_per_word outlines"
+	rejectedMinScore          = "score is below --min_score"
+	rejectedHarder            = "harder to write than the source outline"
+)
+
+// rejection is a candidate that was dropped and why
+type rejection struct {
+	translation string
+	origin      derivation
+	reason      string
+}
+
+// reject records why a candidate was dropped when rejections are being recorded. Only the first
+// reason for each outline and translation is kept. It always returns false so it can be returned
+// from addEntryIfNotPresent.
+func (ctx *augmentationContext) reject(key, value string, origin derivation, reason string) bool {
+	if ctx.rejections == nil {
+		return false
+	}
+	for _, existing := range ctx.rejections[key] {
+		if existing.translation == value {
+			return false
+		}
+	}
+	ctx.rejections[key] = append(ctx.rejections[key], rejection{translation: value, origin: origin, reason: reason})
+	return false
+}
+
+// rejectionFor returns why an outline was dropped for a translation
+func (ctx *augmentationContext) rejectionFor(key, value string) (rejection, bool) {
+	for _, rejection := range ctx.rejections[key] {
+		if rejection.translation == value {
+			return rejection, true
+		}
+	}
+	return rejection{}, false
+}
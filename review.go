@@ -0,0 +1,237 @@
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// reviewItem is a generated entry waiting for a decision
+type reviewItem struct {
+	record provenanceRecord
+	// the source dictionary outline the entry was derived from
+	original string
+	// entries that make up part of the outline
+	nearConflicts []string
+}
+
+// originalOutline follows the sources of an entry back to the source dictionary outline it came from
+func originalOutline(key string, provenance map[string]provenanceRecord) string {
+	seen := map[string]bool{key: true}
+	for {
+		record, ok := provenance[key]
+		if !ok || record.Source == "" || seen[record.Source] {
+			return key
+		}
+		key = record.Source
+		seen[key] = true
+	}
+}
+
+// nearConflicts lists the entries that are written by the strokes on one side of a cut through the
+// outline, like "HAP = hap" for HAP/PEU
+func nearConflicts(key string, dicts ...*map[string]string) []string {
+	strokes := strings.Split(key, "/")
+	var conflicts []string
+	for i := 1; i < len(strokes); i++ {
+		for _, part := range []string{strings.Join(strokes[:i], "/"), strings.Join(strokes[i:], "/")} {
+			for _, dict := range dicts {
+				if value, ok := (*dict)[part]; ok {
+					conflicts = append(conflicts, fmt.Sprintf("%s = %s", part, value))
+					break
+				}
+			}
+		}
+	}
+	return conflicts
+}
+
+// pendingReviewItems returns the entries without a decision, grouped by rule and translation. Entries
+// the provenance marks as manual edits were written by hand, so they aren't reviewed.
+func pendingReviewItems(originalDictionary, additionalEntries *map[string]string, provenance map[string]provenanceRecord,
+	decisions *ReviewDecisions) []reviewItem {
+	var items []reviewItem
+	for key, value := range *additionalEntries {
+		record, ok := provenance[key]
+		if !ok {
+			record = provenanceRecord{Outline: key, Rule: unknownRule}
+		}
+		record.Translation = value
+		if record.Rule == ruleManualEdit || decisions.accepts(key, value) || decisions.rejects(key, value, record.Rule) {
+			continue
+		}
+		items = append(items, reviewItem{
+			record:        record,
+			original:      originalOutline(key, provenance),
+			nearConflicts: nearConflicts(key, originalDictionary, additionalEntries),
+		})
+	}
+	slices.SortFunc(items, func(a, b reviewItem) int {
+		return cmp.Or(
+			cmp.Compare(a.record.Rule, b.record.Rule),
+			cmp.Compare(a.record.Translation, b.record.Translation),
+			cmp.Compare(b.record.Score, a.record.Score),
+			cmp.Compare(a.record.Outline, b.record.Outline),
+		)
+	})
+	return items
+}
+
+// reviewEntries asks for a decision on every item, calling save after each one. Answers are single
+// keys: a accepts, r rejects, w rejects the rule for the word, s skips and q quits. Whitespace is
+// ignored, so answers typed a line at a time work too, and echoAnswers prints each answer for input
+// that isn't echoed by the terminal.
+func reviewEntries(items []reviewItem, in io.Reader, out io.Writer, echoAnswers bool, decisions *ReviewDecisions, save func() error) error {
+	reader := bufio.NewReader(in)
+	for index, item := range items {
+		record := item.record
+		// an earlier answer may have rejected the rule for this word
+		if decisions.rejects(record.Outline, record.Translation, record.Rule) {
+			continue
+		}
+		fmt.Fprintf(out, "\n[%d/%d] %s: %s\n", index+1, len(items), record.Rule, record.Translation)
+		fmt.Fprintf(out, "  %s (score %.2f", record.Outline, record.Score)
+		if record.Source != "" {
+			fmt.Fprintf(out, ", from %s", record.Source)
+		}
+		fmt.Fprintln(out, ")")
+		if item.original != record.Outline {
+			fmt.Fprintf(out, "  source outline: %s\n", item.original)
+		}
+		if len(item.nearConflicts) > 0 {
+			fmt.Fprintf(out, "  near conflicts: %s\n", strings.Join(item.nearConflicts, ", "))
+		}
+
+		fmt.Fprint(out, "(a)ccept, (r)eject, reject the rule for this (w)ord, (s)kip, (q)uit: ")
+		for answered := false; !answered; {
+			key, _, err := reader.ReadRune()
+			if err == io.EOF {
+				return nil
+			} else if err != nil {
+				return err
+			}
+			if unicode.IsSpace(key) {
+				continue
+			}
+			if echoAnswers {
+				fmt.Fprintf(out, "%c\n", key)
+			}
+			answered = true
+			switch unicode.ToLower(key) {
+			case 'a':
+				decisions.accept(record.Outline, record.Translation)
+			case 'r':
+				decisions.reject(record.Outline, record.Translation)
+			case 'w':
+				decisions.rejectRule(record.Translation, record.Rule)
+			case 's':
+				continue
+			case 'q':
+				return nil
+			default:
+				answered = false
+				fmt.Fprint(out, "(a)ccept, (r)eject, reject the rule for this (w)ord, (s)kip, (q)uit: ")
+				continue
+			}
+			if err := save(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// singleKeyInput puts the terminal on stdin into cbreak mode without echo so review answers don't
+// need Enter, and returns a function that restores it. It reports false when stdin isn't a terminal
+// or stty isn't available, and answers are then read a line at a time.
+func singleKeyInput() (restore func(), ok bool) {
+	info, err := os.Stdin.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return nil, false
+	}
+	stty := func(args ...string) ([]byte, error) {
+		cmd := exec.Command("stty", args...)
+		cmd.Stdin = os.Stdin
+		return cmd.Output()
+	}
+	state, err := stty("-g")
+	if err != nil {
+		return nil, false
+	}
+	if _, err := stty("cbreak", "-echo"); err != nil {
+		return nil, false
+	}
+	return func() { stty(strings.TrimSpace(string(state))) }, true
+}
+
+func runReview(args []string) {
+	flags := flag.NewFlagSet("review", flag.ExitOnError)
+	var sourceDictPaths stringList
+	flags.Var(&sourceDictPaths, "lapwing_source", "source dictionary path(s) the augmentations were generated from")
+	augmentationsPath := flags.String("augmentations", "", "generated dictionary to review")
+	provenancePath := flags.String("provenance", "", "provenance file written when the augmentations were generated")
+	decisionsPath := flags.String("decisions", "review-decisions.json", "file to save the decisions to")
+	flags.Parse(args)
+
+	if *augmentationsPath == "" || *provenancePath == "" {
+		fmt.Println("Usage: lapwing_augmentor review --augmentations <augmentations> --provenance <provenance> " +
+			"[--lapwing_source <source-dict> ...] [--decisions <decisions>]")
+		os.Exit(1)
+	}
+
+	originalDictionary := make(map[string]string)
+	for _, sourceDictPath := range sourceDictPaths {
+		if err := readDictionaryInto(sourceDictPath, originalDictionary); err != nil {
+			fmt.Println("Error reading source dictionary:", err)
+			os.Exit(1)
+		}
+	}
+	additionalEntries := make(map[string]string)
+	if err := readDictionaryInto(*augmentationsPath, additionalEntries); err != nil {
+		fmt.Println("Error reading augmentations:", err)
+		os.Exit(1)
+	}
+	provenance, err := readProvenance(*provenancePath)
+	if err != nil {
+		fmt.Println("Error reading provenance:", err)
+		os.Exit(1)
+	}
+	decisions, err := LoadReviewDecisions(*decisionsPath)
+	if err != nil {
+		fmt.Println("Error reading review decisions:", err)
+		os.Exit(1)
+	}
+
+	items := pendingReviewItems(&originalDictionary, &additionalEntries, provenance, decisions)
+	fmt.Println(len(items), "entries to review, decisions are saved to", *decisionsPath)
+	save := func() error { return decisions.save(*decisionsPath) }
+	restore, singleKey := singleKeyInput()
+	if singleKey {
+		// cbreak mode still turns Ctrl-C into an interrupt, and the terminal has to be restored then too
+		interrupts := make(chan os.Signal, 1)
+		signal.Notify(interrupts, os.Interrupt)
+		go func() {
+			<-interrupts
+			restore()
+			fmt.Println()
+			os.Exit(1)
+		}()
+	}
+	err = reviewEntries(items, os.Stdin, os.Stdout, singleKey, decisions, save)
+	if singleKey {
+		restore()
+	}
+	if err != nil {
+		fmt.Println("Error reviewing entries:", err)
+		os.Exit(1)
+	}
+	fmt.Println("\nAccepted", len(decisions.Accepted), "and rejected", len(decisions.Rejected), "outlines so far")
+}
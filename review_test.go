@@ -0,0 +1,108 @@
+package main
+
+import (
+	"io"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestReviewEntries(t *testing.T) {
+	originalDictionary := map[string]string{"HAP": "hap", "HAP/KWREU": "happy", "KAT/-S": "cats"}
+	additionalEntries := map[string]string{
+		"HAP/PEU":  "happy",
+		"HAP/PAOE": "happy",
+		"HA/PAE":   "happy",
+		"KATS":     "cats",
+		"KATZ":     "cats",
+		"KAUT":     "caught",
+	}
+	provenance := map[string]provenanceRecord{
+		"HAP/PEU":  {Outline: "HAP/PEU", Rule: ruleSuffixReplacement, Source: "HAP/KWREU", Score: 8},
+		"HAP/PAOE": {Outline: "HAP/PAOE", Rule: ruleStringReplacement, Source: "HAP/PEU", Score: 7},
+		"HA/PAE":   {Outline: "HA/PAE", Rule: ruleStringReplacement, Source: "HAP/PEU", Score: 6},
+		"KATS":     {Outline: "KATS", Rule: ruleSZFold, Source: "KAT/-S", Score: 11},
+		"KATZ":     {Outline: "KATZ", Rule: ruleSZFold, Source: "KAT/-S", Score: 11},
+		"KAUT":     {Outline: "KAUT", Rule: ruleManualEdit},
+	}
+	decisions := newReviewDecisions()
+	decisions.accept("KATZ", "cats")
+
+	items := pendingReviewItems(&originalDictionary, &additionalEntries, provenance, decisions)
+	var outlines []string
+	for _, item := range items {
+		outlines = append(outlines, item.record.Outline)
+	}
+	if want := []string{"HAP/PAOE", "HA/PAE", "HAP/PEU", "KATS"}; !slices.Equal(outlines, want) {
+		t.Fatalf("pendingReviewItems() = %q, want %q", outlines, want)
+	}
+	if items[0].original != "HAP/KWREU" || !slices.Equal(items[0].nearConflicts, []string{"HAP = hap"}) {
+		t.Fatalf("unexpected review item %+v", items[0])
+	}
+
+	saves := 0
+	save := func() error {
+		saves++
+		return nil
+	}
+	// the unknown answer is asked again, whitespace is ignored and rejecting the rule skips HA/PAE
+	input := strings.NewReader("xw\na r")
+	if err := reviewEntries(items, input, io.Discard, false, decisions, save); err != nil {
+		t.Fatal(err)
+	}
+	if saves != 3 {
+		t.Fatalf("expected 3 saves, got %d", saves)
+	}
+	if !maps.Equal(decisions.Accepted, map[string]string{"KATZ": "cats", "HAP/PEU": "happy"}) {
+		t.Fatalf("unexpected accepted outlines %v", decisions.Accepted)
+	}
+	if !maps.Equal(decisions.Rejected, map[string]string{"KATS": "cats"}) {
+		t.Fatalf("unexpected rejected outlines %v", decisions.Rejected)
+	}
+	if !decisions.rejects("HA/PAE", "happy", ruleStringReplacement) {
+		t.Fatal("expected the string replacement rule to be rejected for happy")
+	}
+}
+
+func TestReviewDecisionsRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "decisions.json")
+	decisions, err := LoadReviewDecisions(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decisions.accept("KATS", "cats")
+	decisions.reject("HA/PAE", "happy")
+	decisions.rejectRule("happy", ruleAlternateSplit)
+	if err := decisions.save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadReviewDecisions(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !loaded.accepts("KATS", "cats") || !loaded.rejects("HA/PAE", "happy", ruleStringReplacement) ||
+		!loaded.rejects("HA/PEU", "happy", ruleAlternateSplit) || loaded.rejects("HA/PEU", "happy", ruleStringReplacement) {
+		t.Fatalf("decisions didn't survive a round trip: %+v", loaded)
+	}
+}
+
+func TestDecisionsAppliedToGeneration(t *testing.T) {
+	ctx := newTestAugmentationContext(map[string]string{"KAT": "cat", "KAT/-S": "cats"})
+	ctx.decisions = newReviewDecisions()
+	ctx.decisions.accept("KA*TS", "cats")
+	ctx.decisions.accept("KAT", "kat")
+	ctx.decisions.reject("KATZ", "cats")
+
+	if added := addAcceptedEntries(ctx); added != 1 || (*ctx.additionalEntries)["KA*TS"] != "cats" {
+		t.Fatalf("expected only KA*TS to be pinned, got %d entries %v", added, *ctx.additionalEntries)
+	}
+	if addEntryIfNotPresent("KATZ", "cats", derivation{rule: ruleSZFold, source: "KAT/-S"}, ctx) {
+		t.Fatal("expected the rejected KATZ not to be generated")
+	}
+	if !addEntryIfNotPresent("KATS", "cats", derivation{rule: ruleSZFold, source: "KAT/-S"}, ctx) {
+		t.Fatal("expected KATS to be generated")
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import "slices"
+
+type splitJudgement struct {
+	score    int
+	known    bool
+	rejected bool
+}
+
+// splitOracle checks alternate splits of a word against some outside source of syllable boundaries
+type splitOracle interface {
+	knowsWord(value string) bool
+	judgeSplit(value string, strokes []string) splitJudgement
+}
+
+// rankAlternateSplits uses the first oracle that knows value to drop alternate splits that don't line up
+// with its syllable boundaries. The remaining ones are sorted so the best matches are added first,
+// with splits the oracle can't judge kept at the end.
+func rankAlternateSplits(value string, alternateStrokes [][]string, oracles []splitOracle) [][]string {
+	var oracle splitOracle
+	for _, candidate := range oracles {
+		if candidate.knowsWord(value) {
+			oracle = candidate
+			break
+		}
+	}
+	if oracle == nil {
+		return alternateStrokes
+	}
+
+	type rankedSplit struct {
+		strokes   []string
+		judgement splitJudgement
+	}
+	var ranked []rankedSplit
+	for _, strokes := range alternateStrokes {
+		judgement := oracle.judgeSplit(value, strokes)
+		if judgement.rejected {
+			continue
+		}
+		ranked = append(ranked, rankedSplit{strokes: strokes, judgement: judgement})
+	}
+
+	slices.SortStableFunc(ranked, func(a, b rankedSplit) int {
+		if a.judgement.known != b.judgement.known {
+			if a.judgement.known {
+				return -1
+			}
+			return 1
+		}
+		return b.judgement.score - a.judgement.score
+	})
+	result := make([][]string, 0, len(ranked))
+	for _, split := range ranked {
+		result = append(result, split.strokes)
+	}
+	return result
+}
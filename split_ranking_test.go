@@ -0,0 +1,63 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func joinStrokeSets(strokeSets [][]string) []string {
+	var joined []string
+	for _, strokes := range strokeSets {
+		joined = append(joined, strings.Join(strokes, "/"))
+	}
+	return joined
+}
+
+func TestRankAlternateSplits(t *testing.T) {
+	pronunciations := &PronunciationDictionary{pronunciations: map[string][][]string{
+		"distribute": {strings.Fields("D IH0 S T R IH1 B Y UW0 T")},
+	}}
+	hyphenation := &HyphenationPatterns{patterns: make(map[string][]int), exceptions: make(map[string][]int)}
+	hyphenation.addException("hap-py")
+	oracles := []splitOracle{pronunciations, hyphenation}
+
+	tests := []struct {
+		name       string
+		value      string
+		alternates string
+		want       []string
+	}{
+		{
+			name:       "pronunciation ranks and drops",
+			value:      "distribute",
+			alternates: "TKEUS/TREUB/AOUT TKEUST/REU/PWAOUT TKEU/STREU/PWAOUT TKEUS/TREUB",
+			want:       []string{"TKEU/STREU/PWAOUT", "TKEUST/REU/PWAOUT", "TKEUS/TREUB"},
+		},
+		{
+			name:       "hyphenation fallback",
+			value:      "happy",
+			alternates: "HAP/EU HA/PEU",
+			want:       []string{"HA/PEU", "HAP/EU"},
+		},
+		{
+			name:       "unknown word",
+			value:      "{^ing}",
+			alternates: "-G",
+			want:       []string{"-G"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var alternates [][]string
+			for _, alternate := range strings.Fields(tt.alternates) {
+				alternates = append(alternates, strings.Split(alternate, "/"))
+			}
+			got := joinStrokeSets(rankAlternateSplits(tt.value, alternates, oracles))
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("rankAlternateSplits(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
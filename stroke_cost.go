@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+)
+
+// keyPosition is the finger that presses a key and the column of the keyboard the key is in
+type keyPosition struct {
+	Finger string `json:"finger"`
+	Column int    `json:"column"`
+}
+
+// StrokeCostModel estimates how hard an outline is to write. Every stroke and every key costs
+// something, a finger that has to reach across columns costs StretchCost per extra column and
+// a finger pressing more than two keys costs CrowdedFingerCost per extra key.
+type StrokeCostModel struct {
+	StrokeCost        float64 `json:"stroke_cost"`
+	KeyCost           float64 `json:"key_cost"`
+	StretchCost       float64 `json:"stretch_cost"`
+	CrowdedFingerCost float64 `json:"crowded_finger_cost"`
+	// how much harder an alternative can be than the outline it came from before it counts as harder
+	HarderTolerance float64 `json:"harder_tolerance"`
+	// keys are written like "S-", "-S", "A" or "*"
+	Keys map[string]keyPosition `json:"keys"`
+}
+
+// DefaultStrokeCostModel uses the usual finger assignment of a steno keyboard
+func DefaultStrokeCostModel() *StrokeCostModel {
+	return &StrokeCostModel{
+		StrokeCost:        2,
+		KeyCost:           0.5,
+		StretchCost:       1,
+		CrowdedFingerCost: 1,
+		// a single extra key, like EU -> AOE, isn't enough to count as harder
+		HarderTolerance: 0.5,
+		Keys: map[string]keyPosition{
+			"#":  {Finger: "left pinky", Column: 0},
+			"S-": {Finger: "left pinky", Column: 0},
+			"T-": {Finger: "left ring", Column: 1},
+			"K-": {Finger: "left ring", Column: 1},
+			"P-": {Finger: "left middle", Column: 2},
+			"W-": {Finger: "left middle", Column: 2},
+			"H-": {Finger: "left index", Column: 3},
+			"R-": {Finger: "left index", Column: 3},
+			"*":  {Finger: "left index", Column: 4},
+			"A":  {Finger: "left thumb", Column: 4},
+			"O":  {Finger: "left thumb", Column: 4},
+			"E":  {Finger: "right thumb", Column: 6},
+			"U":  {Finger: "right thumb", Column: 6},
+			"-F": {Finger: "right index", Column: 6},
+			"-R": {Finger: "right index", Column: 6},
+			"-P": {Finger: "right middle", Column: 7},
+			"-B": {Finger: "right middle", Column: 7},
+			"-L": {Finger: "right ring", Column: 8},
+			"-G": {Finger: "right ring", Column: 8},
+			"-T": {Finger: "right pinky", Column: 9},
+			"-S": {Finger: "right pinky", Column: 9},
+			"-D": {Finger: "right pinky", Column: 10},
+			"-Z": {Finger: "right pinky", Column: 10},
+		},
+	}
+}
+
+// LoadStrokeCostModel reads a JSON stroke cost model. Settings missing from the file keep their
+// defaults and the keys in the file are added to or replace the default keys.
+func LoadStrokeCostModel(path string) (*StrokeCostModel, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	model := DefaultStrokeCostModel()
+	if err := json.Unmarshal(contents, model); err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+// strokeKeys names the keys of a stroke, e.g. "KAT" is K-, A and -T
+func strokeKeys(stroke string) []string {
+	var keys []string
+	addRight := func(right string) {
+		for _, key := range right {
+			keys = append(keys, "-"+string(key))
+		}
+	}
+	if dashIndex := strings.Index(stroke, "-"); dashIndex != -1 {
+		for _, key := range stroke[:dashIndex] {
+			keys = append(keys, string(key)+"-")
+		}
+		addRight(stroke[dashIndex+1:])
+		return keys
+	}
+
+	parts := separateStrokeParts(stroke)
+	for _, key := range parts.Left {
+		if key == '#' {
+			keys = append(keys, "#")
+		} else {
+			keys = append(keys, string(key)+"-")
+		}
+	}
+	for _, key := range parts.Vowels {
+		keys = append(keys, string(key))
+	}
+	addRight(parts.Right)
+	return keys
+}
+
+func (m *StrokeCostModel) strokeCost(stroke string) float64 {
+	keys := strokeKeys(stroke)
+	cost := m.StrokeCost + m.KeyCost*float64(len(keys))
+
+	type fingerUse struct {
+		keys        int
+		leftColumn  int
+		rightColumn int
+	}
+	fingers := make(map[string]*fingerUse)
+	for _, key := range keys {
+		position, ok := m.Keys[key]
+		if !ok {
+			continue
+		}
+		use, ok := fingers[position.Finger]
+		if !ok {
+			use = &fingerUse{leftColumn: position.Column, rightColumn: position.Column}
+			fingers[position.Finger] = use
+		}
+		use.keys++
+		use.leftColumn = min(use.leftColumn, position.Column)
+		use.rightColumn = max(use.rightColumn, position.Column)
+	}
+	for _, use := range fingers {
+		cost += m.StretchCost * float64(use.rightColumn-use.leftColumn)
+		cost += m.CrowdedFingerCost * float64(max(0, use.keys-2))
+	}
+	return cost
+}
+
+// outlineCost is the total cost of writing every stroke of an outline
+func (m *StrokeCostModel) outlineCost(key string) float64 {
+	cost := 0.0
+	for _, stroke := range strings.Split(key, "/") {
+		cost += m.strokeCost(stroke)
+	}
+	return cost
+}
+
+// harder reports whether an outline is harder to write than the outline it was generated from
+func (m *StrokeCostModel) harder(key, source string) bool {
+	return source != "" && m.outlineCost(key) > m.outlineCost(source)+m.HarderTolerance
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestStrokeKeys(t *testing.T) {
+	tests := []struct {
+		stroke string
+		want   []string
+	}{
+		{stroke: "KAT", want: []string{"K-", "A", "-T"}},
+		{stroke: "#SKWR*EUS", want: []string{"#", "S-", "K-", "W-", "R-", "*", "E", "U", "-S"}},
+		{stroke: "-Z", want: []string{"-Z"}},
+		{stroke: "S-G", want: []string{"S-", "-G"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.stroke, func(t *testing.T) {
+			if got := strokeKeys(tt.stroke); !slices.Equal(got, tt.want) {
+				t.Fatalf("strokeKeys(%q) = %q, want %q", tt.stroke, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStrokeCost(t *testing.T) {
+	model := DefaultStrokeCostModel()
+	tests := []struct {
+		name   string
+		stroke string
+		want   float64
+	}{
+		{name: "one key per finger", stroke: "KAT", want: 2 + 1.5},
+		{name: "both rows with one finger", stroke: "TKAOE", want: 2 + 2.5},
+		{name: "pinky reaching across columns", stroke: "KATD", want: 2 + 2 + 1},
+		{name: "crowded pinky", stroke: "KATSDZ", want: 2 + 3 + 1 + 2},
+		{name: "asterisk stretches the index finger", stroke: "HR*EU", want: 2 + 2.5 + 1 + 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := model.strokeCost(tt.stroke); got != tt.want {
+				t.Fatalf("strokeCost(%q) = %v, want %v", tt.stroke, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStrokeCostModelHarder(t *testing.T) {
+	model := DefaultStrokeCostModel()
+	if model.harder("HAPZ", "HAP/-Z") {
+		t.Fatal("folding -Z into the previous stroke should not be harder")
+	}
+	if model.harder("KPHAT", "KHAT") {
+		t.Fatal("a single extra key should be within the tolerance")
+	}
+	if !model.harder("KPHATD", "KHAT") {
+		t.Fatal("an extra key and a pinky stretch should be harder")
+	}
+	model.HarderTolerance = 0
+	if !model.harder("KPHAT", "KHAT") {
+		t.Fatal("an extra key should be harder without a tolerance")
+	}
+	if model.harder("KAT", "") {
+		t.Fatal("outlines without a source can't be harder")
+	}
+}
+
+func TestLoadStrokeCostModel(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "model.json")
+	contents := `{"stroke_cost": 5, "keys": {"-D": {"finger": "right ring", "column": 8}}}`
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+	model, err := LoadStrokeCostModel(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if model.StrokeCost != 5 || model.KeyCost != 0.5 {
+		t.Fatalf("expected stroke_cost to be replaced and key_cost kept, got %+v", model)
+	}
+	if model.Keys["-D"].Finger != "right ring" || model.Keys["-T"].Finger != "right pinky" {
+		t.Fatalf("expected -D to be reassigned and the other keys kept, got %+v", model.Keys)
+	}
+}
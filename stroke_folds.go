@@ -0,0 +1,109 @@
+package main
+
+import "strings"
+
+// strokeFold is an outline made by folding a stroke into its neighbour
+type strokeFold struct {
+	strokes []string
+	rule    string
+}
+
+// strokeParts splits a stroke into its banks, also accepting a dash between the left and right bank
+func strokeParts(stroke string) StenoParts {
+	if left, right, ok := strings.Cut(stroke, "-"); ok {
+		return StenoParts{Left: left, Right: right, Valid: !strings.Contains(right, "-")}
+	}
+	return separateStrokeParts(stroke)
+}
+
+// joinStrokeParts writes the banks as a stroke, with a leading dash for right bank only strokes
+func joinStrokeParts(parts StenoParts) string {
+	if isRightBankOnly(parts) {
+		return "-" + parts.Right
+	}
+	return parts.Left + parts.Vowels + parts.Right
+}
+
+// foldAdjacentStrokes returns the outlines made by folding one right bank only stroke into the stroke
+// before it, like KAT/-S -> KATS, or one left bank only stroke into the stroke after it, like
+// S/TKRAOEUF -> STKRAOEUF, where the combined stroke is in steno order
+func foldAdjacentStrokes(strokes []string) []strokeFold {
+	var folds []strokeFold
+	for i := 0; i+1 < len(strokes); i++ {
+		first, second := strokeParts(strokes[i]), strokeParts(strokes[i+1])
+		if !first.Valid || !second.Valid {
+			continue
+		}
+		merged, rule := first, ""
+		switch {
+		case isRightBankOnly(second):
+			merged.Right += second.Right
+			rule = ruleDashStrokeFold
+		case first.Vowels == "" && first.Right == "" && first.Left != "" && !strings.Contains(strokes[i], "-"):
+			merged = second
+			merged.Left = first.Left + second.Left
+			rule = ruleLeftStrokeFold
+		default:
+			continue
+		}
+		if stroke, ok := validMergedStroke(merged); ok {
+			folds = append(folds, strokeFold{strokes: replaceStrokePair(strokes, i, stroke), rule: rule})
+		}
+	}
+	return folds
+}
+
+func isRightBankOnly(parts StenoParts) bool {
+	return parts.Left == "" && parts.Vowels == "" && parts.Right != ""
+}
+
+// validMergedStroke writes merged banks as a stroke if they are in steno order. Banks that would
+// need a dash in the middle of the stroke, like K and T, are rejected because the rest of the tool
+// doesn't read those strokes.
+func validMergedStroke(merged StenoParts) (string, bool) {
+	stroke := joinStrokeParts(merged)
+	if !isValidOrder(merged.Left, "#ZSTKPWHRV") || !isValidOrder(merged.Right, "FRPBLGTSDZ") || !isValidStenoOrder(stroke) {
+		return "", false
+	}
+	if reparsed := strokeParts(stroke); reparsed.Left != merged.Left || reparsed.Right != merged.Right {
+		return "", false
+	}
+	return stroke, true
+}
+
+// replaceStrokePair replaces strokes i and i+1 with a single stroke
+func replaceStrokePair(strokes []string, i int, stroke string) []string {
+	folded := make([]string, 0, len(strokes)-1)
+	folded = append(folded, strokes[:i]...)
+	folded = append(folded, stroke)
+	return append(folded, strokes[i+2:]...)
+}
+
+// addAdjacentStrokeFolds adds every outline reachable by repeatedly folding adjacent strokes, so chains
+// like /-G/-S fold into one chord. Each fold is derived from the last fold on the way that was added.
+// addFolded is called with every folded outline, added or not.
+func addAdjacentStrokeFolds(key, value string, ctx *augmentationContext, addFolded func(key string)) {
+	type pendingFold struct {
+		strokes []string
+		source  string
+	}
+	queue := []pendingFold{{strokes: strings.Split(key, "/"), source: key}}
+	seen := map[string]bool{key: true}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, fold := range foldAdjacentStrokes(current.strokes) {
+			newKey := strings.Join(fold.strokes, "/")
+			if seen[newKey] {
+				continue
+			}
+			seen[newKey] = true
+			source := current.source
+			if addEntryIfNotPresent(newKey, value, derivation{rule: fold.rule, source: source}, ctx) {
+				source = ctx.addedOutline(newKey, value)
+			}
+			addFolded(newKey)
+			queue = append(queue, pendingFold{strokes: fold.strokes, source: source})
+		}
+	}
+}
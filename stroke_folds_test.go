@@ -0,0 +1,108 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFoldAdjacentStrokes(t *testing.T) {
+	tests := []struct {
+		name     string
+		strokes  []string
+		expected []strokeFold
+	}{
+		{
+			name:     "final dash stroke",
+			strokes:  []string{"KAT", "-S"},
+			expected: []strokeFold{{strokes: []string{"KATS"}, rule: ruleDashStrokeFold}},
+		},
+		{
+			name:    "chained dash strokes",
+			strokes: []string{"PWA", "-G", "-S"},
+			expected: []strokeFold{
+				{strokes: []string{"PWAG", "-S"}, rule: ruleDashStrokeFold},
+				{strokes: []string{"PWA", "-GS"}, rule: ruleDashStrokeFold},
+			},
+		},
+		{
+			name:     "dash stroke out of steno order",
+			strokes:  []string{"RAOEUT", "-G"},
+			expected: nil,
+		},
+		{
+			name:     "repeated key",
+			strokes:  []string{"WAUBG", "-G"},
+			expected: nil,
+		},
+		{
+			name:     "left bank stroke",
+			strokes:  []string{"S", "TKRAOEUF"},
+			expected: []strokeFold{{strokes: []string{"STKRAOEUF"}, rule: ruleLeftStrokeFold}},
+		},
+		{
+			name:     "left bank stroke out of steno order",
+			strokes:  []string{"R", "TKAOEUF"},
+			expected: nil,
+		},
+		{
+			name:     "no dash needed when the right bank can't be read as left bank keys",
+			strokes:  []string{"TK", "-G"},
+			expected: []strokeFold{{strokes: []string{"TKG"}, rule: ruleDashStrokeFold}},
+		},
+		{
+			name:     "no fold where the right bank would be read as left bank keys",
+			strokes:  []string{"S", "-T"},
+			expected: nil,
+		},
+		{
+			name:     "no fold needing a dash in the middle",
+			strokes:  []string{"K", "-T"},
+			expected: nil,
+		},
+		{
+			name:     "strokes with vowels",
+			strokes:  []string{"KAT", "KAT"},
+			expected: nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			folds := foldAdjacentStrokes(test.strokes)
+			if !reflect.DeepEqual(folds, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, folds)
+			}
+		})
+	}
+}
+
+func TestAddAdjacentStrokeFolds(t *testing.T) {
+	ctx := newTestAugmentationContext(map[string]string{"PWA/-G/-S": "bags"})
+	var folded []string
+	addAdjacentStrokeFolds("PWA/-G/-S", "bags", ctx, func(key string) { folded = append(folded, key) })
+
+	expected := map[string]string{"PWAG/-S": "bags", "PWA/-GS": "bags", "PWAGS": "bags"}
+	if !reflect.DeepEqual(*ctx.additionalEntries, expected) {
+		t.Errorf("expected %v, got %v", expected, *ctx.additionalEntries)
+	}
+	if !reflect.DeepEqual(folded, []string{"PWAG/-S", "PWA/-GS", "PWAGS"}) {
+		t.Errorf("expected every fold to be passed on, got %v", folded)
+	}
+	if record := ctx.provenance["PWAGS"]; record.Rule != ruleDashStrokeFold || record.Source != "PWAG/-S" || record.Depth != 2 {
+		t.Errorf("expected PWAGS to be folded from PWAG/-S, got %+v", record)
+	}
+}
+
+func TestAddAdjacentStrokeFoldsFromAsteriskVariant(t *testing.T) {
+	ctx := newTestAugmentationContext(map[string]string{"PWA/-G/-S": "bags", "PWAG/-S": "bag's"})
+	ctx.asteriskFallback = true
+	addAdjacentStrokeFolds("PWA/-G/-S", "bags", ctx, func(string) {})
+
+	if (*ctx.additionalEntries)["PWAG/*S"] != "bags" {
+		t.Fatalf("expected PWAG/*S to be added for bags, got %v", *ctx.additionalEntries)
+	}
+	// the taken PWAG/-S isn't in the output, so the next fold comes from the variant that is
+	if record := ctx.provenance["PWAGS"]; record.Source != "PWAG/*S" {
+		t.Errorf("expected PWAGS to be folded from PWAG/*S, got %+v", record)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"slices"
+	"strings"
+)
+
+// suffixInventory is built from the {^...} entries of the source dictionaries, so suffix strokes added
+// to Lapwing or a personal dictionary are folded without code changes
+type suffixInventory struct {
+	// suffix stroke -> suffix, like -G -> {^ing}
+	suffixes map[string]string
+	// suffix -> the right bank chords of its strokes, like {^s} -> S, Z
+	chords map[string][]string
+}
+
+func isSuffixTranslation(value string) bool {
+	return len(value) > len("{^}") && strings.HasPrefix(value, "{^") && strings.HasSuffix(value, "}") && !strings.HasSuffix(value, "^}")
+}
+
+// newSuffixInventory collects the single stroke suffix entries that only use the right bank, since
+// those are the ones that can be folded into the stroke before them
+func newSuffixInventory(dict *map[string]string) *suffixInventory {
+	inventory := &suffixInventory{suffixes: make(map[string]string), chords: make(map[string][]string)}
+	for _, key := range sortedMapKeys(dict) {
+		value := (*dict)[key]
+		if strings.Contains(key, "/") || !isSuffixTranslation(value) {
+			continue
+		}
+		parts := strokeParts(key)
+		if !parts.Valid || !isRightBankOnly(parts) {
+			continue
+		}
+		inventory.suffixes[key] = value
+		if !slices.Contains(inventory.chords[value], parts.Right) {
+			inventory.chords[value] = append(inventory.chords[value], parts.Right)
+		}
+	}
+	return inventory
+}
+
+// suffixFolds returns the outlines made by folding a suffix stroke into the stroke before it using the
+// other chords the dictionaries have for the same suffix, like KAT/-S -> KATZ when -Z is also {^s}.
+// Folding the suffix stroke's own chord is left to foldAdjacentStrokes.
+func (inventory *suffixInventory) suffixFolds(strokes []string) [][]string {
+	var folds [][]string
+	for i := 1; i < len(strokes); i++ {
+		suffix, ok := inventory.suffixes[strokes[i]]
+		if !ok {
+			continue
+		}
+		previous := strokeParts(strokes[i-1])
+		if !previous.Valid {
+			continue
+		}
+		own := strokeParts(strokes[i]).Right
+		for _, chord := range inventory.chords[suffix] {
+			if chord == own {
+				continue
+			}
+			merged := previous
+			merged.Right += chord
+			if stroke, ok := validMergedStroke(merged); ok {
+				folds = append(folds, replaceStrokePair(strokes, i-1, stroke))
+			}
+		}
+	}
+	return folds
+}
+
+func addSuffixFolds(key, value string, inventory *suffixInventory, ctx *augmentationContext) {
+	for _, folded := range inventory.suffixFolds(strings.Split(key, "/")) {
+		addEntryIfNotPresent(strings.Join(folded, "/"), value, derivation{rule: ruleSuffixFold, source: key}, ctx)
+	}
+}
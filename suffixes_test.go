@@ -0,0 +1,127 @@
+package main
+
+import (
+	"io"
+	"log"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestNewSuffixInventory(t *testing.T) {
+	inventory := newSuffixInventory(&map[string]string{
+		"-G":       "{^ing}",
+		"-S":       "{^s}",
+		"-Z":       "{^s}",
+		"-GS":      "{^tion}",
+		"SHUPB":    "{^tion}",
+		"HREU":     "{^ly}",
+		"STKPW":    "{^}",
+		"KWRE":     "{re^}",
+		"KAT":      "cat",
+		"-D/-D":    "{^ed}",
+		"TPHAEUGS": "nation",
+	})
+
+	expectedSuffixes := map[string]string{"-G": "{^ing}", "-S": "{^s}", "-Z": "{^s}", "-GS": "{^tion}"}
+	if !reflect.DeepEqual(inventory.suffixes, expectedSuffixes) {
+		t.Errorf("expected suffix strokes %v, got %v", expectedSuffixes, inventory.suffixes)
+	}
+	expectedChords := map[string][]string{"{^ing}": {"G"}, "{^s}": {"S", "Z"}, "{^tion}": {"GS"}}
+	if !reflect.DeepEqual(inventory.chords, expectedChords) {
+		t.Errorf("expected chords %v, got %v", expectedChords, inventory.chords)
+	}
+}
+
+func TestSuffixFolds(t *testing.T) {
+	inventory := newSuffixInventory(&map[string]string{
+		"-S":  "{^s}",
+		"-Z":  "{^s}",
+		"-G":  "{^ing}",
+		"-GZ": "{^ings}",
+	})
+
+	tests := []struct {
+		name     string
+		strokes  []string
+		expected [][]string
+	}{
+		{
+			name:     "other chord of the suffix",
+			strokes:  []string{"KAT", "-S"},
+			expected: [][]string{{"KATZ"}},
+		},
+		{
+			name:     "suffix in the middle of the outline",
+			strokes:  []string{"PWA", "-Z", "KAT"},
+			expected: [][]string{{"PWAS", "KAT"}},
+		},
+		{
+			name:     "other chord out of steno order",
+			strokes:  []string{"PWAD", "-Z"},
+			expected: nil,
+		},
+		{
+			name:     "suffix with a single chord",
+			strokes:  []string{"PWA", "-G"},
+			expected: nil,
+		},
+		{
+			name:     "not a suffix stroke",
+			strokes:  []string{"KAT", "-D"},
+			expected: nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			folds := inventory.suffixFolds(test.strokes)
+			if !reflect.DeepEqual(folds, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, folds)
+			}
+		})
+	}
+}
+
+func TestSuffixChordSwapsFollowTheInventory(t *testing.T) {
+	options := augmentationOptions{
+		casePolicy:  caseVariantPolicy{mode: caseVariantsOff},
+		strokeCosts: DefaultStrokeCostModel(),
+		minScore:    math.Inf(-1),
+	}
+	tests := []struct {
+		name     string
+		dict     map[string]string
+		outline  string
+		expected bool
+	}{
+		{
+			name:     "S and Z are both {^s}",
+			dict:     map[string]string{"-S": "{^s}", "-Z": "{^s}", "KATS": "cats"},
+			outline:  "KATZ",
+			expected: true,
+		},
+		{
+			name:     "Z isn't a suffix stroke",
+			dict:     map[string]string{"-S": "{^s}", "KATS": "cats"},
+			outline:  "KATZ",
+			expected: false,
+		},
+		{
+			name:     "second {^ing} chord next to the hand-written -G swap",
+			dict:     map[string]string{"-G": "{^ing}", "-LG": "{^ing}", "SEUPBG": "sing"},
+			outline:  "SEUPBLG",
+			expected: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := augmentDictionary(test.dict, options, log.New(io.Discard, "", 0))
+			_, ok := (*ctx.additionalEntries)[test.outline]
+			if ok != test.expected {
+				t.Errorf("expected %s to be generated: %v, got %v", test.outline, test.expected, *ctx.additionalEntries)
+			}
+		})
+	}
+}